@@ -0,0 +1,215 @@
+// Package auth authenticates API requests with bearer tokens and checks that
+// the token is allowed to call the requested method.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"github.com/travis-ci/imaged/db"
+	"github.com/twitchtv/twirp"
+	"net/http"
+	"strings"
+)
+
+// Scope is a level of access to the API that a token can be granted.
+//
+// Scopes build on each other: the build scope can also do anything the read
+// scope can, and the admin scope can do anything.
+type Scope string
+
+// These are the scopes that a token can be granted.
+const (
+	ScopeRead  Scope = "read"
+	ScopeBuild Scope = "build"
+	ScopeAdmin Scope = "admin"
+)
+
+var scopeLevels = map[Scope]int{
+	ScopeRead:  1,
+	ScopeBuild: 2,
+	ScopeAdmin: 3,
+}
+
+// methodScopes is the scope needed to call each API method. Methods that are
+// not listed need the admin scope.
+var methodScopes = map[string]Scope{
+	"ListBuilds":       ScopeRead,
+	"GetBuild":         ScopeRead,
+	"GetLastBuild":     ScopeRead,
+	"TailBuildLog":     ScopeRead,
+	"ListSchedules":    ScopeRead,
+	"ListTemplates":    ScopeRead,
+	"GetTemplate":      ScopeRead,
+	"DownloadRecord":   ScopeRead,
+	"GetRecordURL":     ScopeRead,
+	"StartBuild":       ScopeBuild,
+	"CancelBuild":      ScopeBuild,
+	"RetryBuild":       ScopeBuild,
+	"AttachRecord":     ScopeBuild,
+	"SetBuildReleased": ScopeBuild,
+	"CreateSchedule":   ScopeAdmin,
+	"DeleteSchedule":   ScopeAdmin,
+	"ListAuditEvents":  ScopeAdmin,
+}
+
+// ParseScope checks that a scope name is valid.
+func ParseScope(s string) (Scope, error) {
+	scope := Scope(s)
+	if _, ok := scopeLevels[scope]; !ok {
+		return "", errors.Errorf("unknown scope %q", s)
+	}
+
+	return scope, nil
+}
+
+// Allows returns whether a token with the given scopes can do something that
+// needs a scope.
+//
+// Unknown scopes never allow anything, and nothing is allowed to do something
+// that needs an unknown scope.
+func Allows(scopes []string, needed Scope) bool {
+	neededLevel, ok := scopeLevels[needed]
+	if !ok {
+		return false
+	}
+
+	for _, s := range scopes {
+		if scopeLevels[Scope(s)] >= neededLevel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GenerateToken creates a new random token and the hash that should be saved
+// for it.
+//
+// Only the hash is saved, so the token can only be shown once.
+func GenerateToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", errors.Wrap(err, "could not generate token")
+	}
+
+	token = hex.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken hashes a token the same way as when it was saved.
+//
+// Tokens are long and random, so a plain SHA-256 is enough to keep them safe
+// if the database leaks.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type contextKey int
+
+const (
+	tokenKey contextKey = iota
+	principalKey
+)
+
+// WithPrincipal returns a context that says who is making a request.
+//
+// Requests that don't come through the API, like scheduled builds, use this to
+// record what started them.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// Principal returns who is making a request, or "" if the request was not
+// authenticated.
+func Principal(ctx context.Context) string {
+	p, _ := ctx.Value(principalKey).(string)
+	return p
+}
+
+// Authenticator checks the bearer tokens of API requests.
+//
+// Its Handler reads the token from each HTTP request, and its RequestRouted
+// hook looks the token up once Twirp knows which method is being called.
+type Authenticator struct {
+	DB *db.Connection
+}
+
+// Handler wraps the Twirp handler so the hooks can see the request's token.
+func (a *Authenticator) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header := req.Header.Get("Authorization")
+		if len(header) > len("bearer ") && strings.EqualFold(header[:len("bearer ")], "bearer ") {
+			token := strings.TrimSpace(header[len("bearer "):])
+			req = req.WithContext(context.WithValue(req.Context(), tokenKey, token))
+		}
+
+		h.ServeHTTP(w, req)
+	})
+}
+
+// RequestRouted is a Twirp hook that rejects requests without a valid token,
+// or whose token doesn't have the scope needed for the method.
+//
+// The token's name is saved as the principal of the request.
+func (a *Authenticator) RequestRouted(ctx context.Context) (context.Context, error) {
+	method, _ := twirp.MethodName(ctx)
+	needed, ok := methodScopes[method]
+	if !ok {
+		needed = ScopeAdmin
+	}
+
+	token, _ := ctx.Value(tokenKey).(string)
+	return a.authenticate(ctx, token, needed)
+}
+
+// RequireScopes wraps a plain HTTP handler so that requests need a valid token
+// with the scope given for their HTTP method. Methods that are not listed need
+// the admin scope.
+func (a *Authenticator) RequireScopes(h http.Handler, scopes map[string]Scope) http.Handler {
+	return a.Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		needed, ok := scopes[req.Method]
+		if !ok {
+			needed = ScopeAdmin
+		}
+
+		token, _ := req.Context().Value(tokenKey).(string)
+		ctx, err := a.authenticate(req.Context(), token, needed)
+		if err != nil {
+			twerr := err.(twirp.Error)
+			http.Error(w, twerr.Msg(), twirp.ServerHTTPStatusFromErrorCode(twerr.Code()))
+			return
+		}
+
+		h.ServeHTTP(w, req.WithContext(ctx))
+	}))
+}
+
+// authenticate checks that a token is valid and has a scope, returning a
+// context with the token's name as the principal.
+//
+// Errors are always Twirp errors.
+func (a *Authenticator) authenticate(ctx context.Context, token string, needed Scope) (context.Context, error) {
+	if token == "" {
+		return ctx, twirp.NewError(twirp.Unauthenticated, "a bearer token is required")
+	}
+
+	t, err := a.DB.GetTokenByHash(ctx, HashToken(token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ctx, twirp.NewError(twirp.Unauthenticated, "invalid bearer token")
+		}
+
+		return ctx, twirp.InternalErrorWith(err)
+	}
+
+	if !Allows(t.Scopes, needed) {
+		return ctx, twirp.NewError(twirp.PermissionDenied, "token needs the "+string(needed)+" scope")
+	}
+
+	return WithPrincipal(ctx, t.Name), nil
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		scopes []string
+		needed Scope
+		want   bool
+	}{
+		{nil, ScopeRead, false},
+		{[]string{}, ScopeRead, false},
+		{[]string{"read"}, ScopeRead, true},
+		{[]string{"read"}, ScopeBuild, false},
+		{[]string{"read"}, ScopeAdmin, false},
+		{[]string{"build"}, ScopeRead, true},
+		{[]string{"build"}, ScopeBuild, true},
+		{[]string{"build"}, ScopeAdmin, false},
+		{[]string{"admin"}, ScopeRead, true},
+		{[]string{"admin"}, ScopeBuild, true},
+		{[]string{"admin"}, ScopeAdmin, true},
+		{[]string{"read", "admin"}, ScopeAdmin, true},
+		{[]string{"unknown"}, ScopeRead, false},
+		{[]string{"ADMIN"}, ScopeRead, false},
+		{[]string{"admin"}, Scope("unknown"), false},
+		{[]string{"unknown"}, Scope("unknown"), false},
+		{[]string{""}, Scope(""), false},
+	}
+
+	for _, tt := range tests {
+		if got := Allows(tt.scopes, tt.needed); got != tt.want {
+			t.Errorf("Allows(%q, %q) = %v, want %v", tt.scopes, tt.needed, got, tt.want)
+		}
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		scope string
+		valid bool
+	}{
+		{"read", true},
+		{"build", true},
+		{"admin", true},
+		{"", false},
+		{"Read", false},
+		{"write", false},
+	}
+
+	for _, tt := range tests {
+		scope, err := ParseScope(tt.scope)
+		if tt.valid && (err != nil || scope != Scope(tt.scope)) {
+			t.Errorf("ParseScope(%q) = %q, %v, want %q", tt.scope, scope, err, tt.scope)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("ParseScope(%q) should have returned an error", tt.scope)
+		}
+	}
+}
+
+func TestMethodScopes(t *testing.T) {
+	for method, scope := range methodScopes {
+		if _, err := ParseScope(string(scope)); err != nil {
+			t.Errorf("method %s needs unknown scope %q", method, scope)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		header string
+		token  string
+	}{
+		{"", ""},
+		{"Bearer abc123", "abc123"},
+		{"bearer abc123", "abc123"},
+		{"BEARER  abc123 ", "abc123"},
+		{"Bearer ", ""},
+		{"Basic abc123", ""},
+		{"abc123", ""},
+	}
+
+	for _, tt := range tests {
+		var token string
+		h := (&Authenticator{}).Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			token, _ = req.Context().Value(tokenKey).(string)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/twirp/travis.imaged.Images/ListBuilds", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		if token != tt.token {
+			t.Errorf("Handler with Authorization %q found token %q, want %q", tt.header, token, tt.token)
+		}
+	}
+}
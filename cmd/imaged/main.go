@@ -2,16 +2,26 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
+	"github.com/travis-ci/imaged/auth"
 	"github.com/travis-ci/imaged/db"
+	"github.com/travis-ci/imaged/retention"
 	rpc "github.com/travis-ci/imaged/rpc/images"
+	"github.com/travis-ci/imaged/schedule"
+	"github.com/travis-ci/imaged/secrets"
 	"github.com/travis-ci/imaged/server"
 	"github.com/travis-ci/imaged/storage"
+	"github.com/travis-ci/imaged/templates"
+	"github.com/travis-ci/imaged/webhook"
 	"github.com/travis-ci/imaged/worker"
 	"github.com/twitchtv/twirp"
 	"github.com/urfave/cli"
 	"net/http"
 	"os"
+	"time"
 )
 
 func main() {
@@ -36,34 +46,223 @@ func main() {
 		},
 		cli.StringFlag{
 			Name:   "bucket, b",
-			Usage:  "S3 bucket name for storing build records",
+			Usage:  "where to store build records: an S3 bucket name, an s3://bucket URL, or a file:///path URL for a local directory",
 			EnvVar: "IMAGED_RECORD_BUCKET",
 		},
+		cli.StringFlag{
+			Name:   "public-url",
+			Usage:  "base URL where clients can reach imaged, used for record URLs when records are stored in a local directory",
+			EnvVar: "IMAGED_PUBLIC_URL",
+			Value:  "http://localhost:8080",
+		},
+		cli.StringFlag{
+			Name:   "storage-signing-key",
+			Usage:  "secret used to sign record URLs when records are stored in a local directory; a random one is used if unset, so URLs only work until imaged restarts",
+			EnvVar: "IMAGED_STORAGE_SIGNING_KEY",
+		},
 		cli.StringFlag{
 			Name:   "templates-path",
 			Usage:  "local path where Packer templates Git repo should be checked out",
 			EnvVar: "IMAGED_TEMPLATES_PATH",
 			Value:  "/templates",
 		},
+		cli.IntFlag{
+			Name:   "workers",
+			Usage:  "number of builds to run in parallel; each worker gets its own numbered clone of the templates next to the templates path",
+			EnvVar: "IMAGED_WORKERS",
+			Value:  1,
+		},
 		cli.StringFlag{
 			Name:   "templates-url",
 			Usage:  "URL for Git repo containing Packer templates",
 			EnvVar: "IMAGED_TEMPLATES_URL",
 		},
+		cli.StringFlag{
+			Name:   "templates-ssh-key",
+			Usage:  "local path to an SSH private key for cloning the templates repo and its submodules over SSH",
+			EnvVar: "IMAGED_TEMPLATES_SSH_KEY",
+		},
+		cli.StringFlag{
+			Name:   "templates-ssh-key-passphrase",
+			Usage:  "passphrase for the templates SSH private key, if it's encrypted",
+			EnvVar: "IMAGED_TEMPLATES_SSH_KEY_PASSPHRASE",
+		},
+		cli.StringFlag{
+			Name:   "templates-known-hosts",
+			Usage:  "local path to a known_hosts file for checking SSH host keys of the templates repo (defaults to SSH_KNOWN_HOSTS or ~/.ssh/known_hosts)",
+			EnvVar: "IMAGED_TEMPLATES_KNOWN_HOSTS",
+		},
+		cli.StringFlag{
+			Name:   "templates-username",
+			Usage:  "username for cloning the templates repo and its submodules over HTTPS",
+			EnvVar: "IMAGED_TEMPLATES_USERNAME",
+		},
+		cli.StringFlag{
+			Name:   "templates-password",
+			Usage:  "password or access token for cloning the templates repo and its submodules over HTTPS",
+			EnvVar: "IMAGED_TEMPLATES_PASSWORD",
+		},
+		cli.StringSliceFlag{
+			Name:   "templates-credential-host",
+			Usage:  "host that the templates username and password are sent to, over HTTPS only (can be given more than once, defaults to the host of the templates URL)",
+			EnvVar: "IMAGED_TEMPLATES_CREDENTIAL_HOSTS",
+		},
+		cli.DurationFlag{
+			Name:   "templates-fetch-interval",
+			Usage:  "how often to fetch the latest commits of the templates repo for listing templates (0 only fetches on push and before builds)",
+			EnvVar: "IMAGED_TEMPLATES_FETCH_INTERVAL",
+			Value:  time.Minute,
+		},
 		cli.StringFlag{
 			Name:   "packer",
 			Usage:  "path to the Packer executable",
 			EnvVar: "IMAGED_PACKER_PATH",
 			Value:  "/bin/packer",
 		},
+		cli.BoolFlag{
+			Name:   "validate-templates",
+			Usage:  "run packer validate on a template before accepting a build of it",
+			EnvVar: "IMAGED_VALIDATE_TEMPLATES",
+		},
 		cli.StringFlag{
 			Name:   "secrets",
-			Usage:  "local path to a file containing secrets for Linux Ansible playbooks",
+			Usage:  "local path to a file containing secrets for Linux Ansible playbooks, installed for every template if it exists",
 			EnvVar: "IMAGED_ANSIBLE_SECRETS_FILE",
 		},
+		cli.StringFlag{
+			Name:   "secrets-config",
+			Usage:  "local path to a YAML file mapping templates to secret files, Packer variables and environment variables",
+			EnvVar: "IMAGED_SECRETS_CONFIG",
+		},
+		cli.StringSliceFlag{
+			Name:   "redact-env",
+			Usage:  "environment variable whose value is redacted from build logs, along with the values of the build's secrets (can be given more than once)",
+			EnvVar: "IMAGED_REDACT_ENV",
+		},
+		cli.DurationFlag{
+			Name:   "poll-interval",
+			Usage:  "how often to check the build queue for builds queued by other imaged instances",
+			EnvVar: "IMAGED_POLL_INTERVAL",
+			Value:  10 * time.Second,
+		},
+		cli.DurationFlag{
+			Name:   "build-timeout",
+			Usage:  "how long a build can run when neither the build nor its template sets a timeout (0 for no timeout)",
+			EnvVar: "IMAGED_BUILD_TIMEOUT",
+			Value:  6 * time.Hour,
+		},
+		cli.IntFlag{
+			Name:   "max-attempts",
+			Usage:  "how many times to attempt a build before leaving it failed; failed and timed out builds are retried",
+			EnvVar: "IMAGED_MAX_ATTEMPTS",
+			Value:  1,
+		},
+		cli.StringSliceFlag{
+			Name:   "webhook-url",
+			Usage:  "URL to POST a JSON payload to when a build starts or finishes (can be given more than once)",
+			EnvVar: "IMAGED_WEBHOOK_URLS",
+		},
+		cli.StringFlag{
+			Name:   "webhook-secret",
+			Usage:  "secret used to sign webhook payloads with HMAC-SHA256",
+			EnvVar: "IMAGED_WEBHOOK_SECRET",
+		},
+		cli.StringFlag{
+			Name:   "github-secret",
+			Usage:  "secret of the GitHub push webhook for the templates repo; enables building changed templates on push",
+			EnvVar: "IMAGED_GITHUB_SECRET",
+		},
+		cli.StringSliceFlag{
+			Name:   "github-branch",
+			Usage:  "branch of the templates repo that triggers builds when pushed to (can be given more than once, defaults to master)",
+			EnvVar: "IMAGED_GITHUB_BRANCHES",
+		},
+		cli.IntFlag{
+			Name:   "retention-keep-builds",
+			Usage:  "number of most recent builds of each template whose records are always kept",
+			EnvVar: "IMAGED_RETENTION_KEEP_BUILDS",
+			Value:  10,
+		},
+		cli.DurationFlag{
+			Name:   "retention-max-age",
+			Usage:  "how long to keep records of succeeded builds after they finish, like 2160h for 90 days (0 keeps them forever)",
+			EnvVar: "IMAGED_RETENTION_MAX_AGE",
+		},
+		cli.DurationFlag{
+			Name:   "retention-failed-log-max-age",
+			Usage:  "how long to keep build logs of failed, cancelled and timed out builds after they finish, like 720h for 30 days (0 keeps them forever); their other records are always kept",
+			EnvVar: "IMAGED_RETENTION_FAILED_LOG_MAX_AGE",
+		},
+		cli.DurationFlag{
+			Name:   "retention-interval",
+			Usage:  "how often to delete expired records",
+			EnvVar: "IMAGED_RETENTION_INTERVAL",
+			Value:  time.Hour,
+		},
+		cli.BoolFlag{
+			Name:   "retention-dry-run",
+			Usage:  "log expired records instead of deleting them",
+			EnvVar: "IMAGED_RETENTION_DRY_RUN",
+		},
+		cli.BoolFlag{
+			Name:   "auth",
+			Usage:  "require a bearer token for API requests (create tokens with the create-token command)",
+			EnvVar: "IMAGED_AUTH",
+		},
+		cli.StringFlag{
+			Name:   "orphan-policy",
+			Usage:  "what to do with builds left unfinished when imaged last stopped (fail or requeue)",
+			EnvVar: "IMAGED_ORPHAN_POLICY",
+			Value:  "fail",
+		},
+		cli.DurationFlag{
+			Name:   "orphan-timeout",
+			Usage:  "how long a build can go without a heartbeat from the imaged instance running it before it's treated as orphaned",
+			EnvVar: "IMAGED_ORPHAN_TIMEOUT",
+			Value:  5 * time.Minute,
+		},
+		cli.StringFlag{
+			Name:   "instance-id",
+			Usage:  "name of this imaged instance, which owns the builds it runs and should stay the same across restarts (defaults to the hostname)",
+			EnvVar: "IMAGED_INSTANCE_ID",
+		},
 	}
 
 	app.Action = Run
+	app.Commands = []cli.Command{
+		{
+			Name:   "create-token",
+			Usage:  "create an API token and print it",
+			Action: CreateToken,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "name",
+					Usage: "unique name of the token, which is recorded on the builds it starts",
+				},
+				cli.StringSliceFlag{
+					Name:  "scope",
+					Usage: "scope to grant the token: read, build or admin (can be given more than once, defaults to read)",
+				},
+			},
+		},
+		{
+			Name:   "collect-records",
+			Usage:  "delete expired records once, using the retention flags",
+			Action: CollectRecords,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "log expired records instead of deleting them",
+				},
+			},
+		},
+		{
+			Name:      "revoke-token",
+			Usage:     "delete an API token so it can no longer be used",
+			ArgsUsage: "NAME",
+			Action:    RevokeToken,
+		},
+	}
 
 	err := app.Run(os.Args)
 	if err != nil {
@@ -84,32 +283,72 @@ func Run(c *cli.Context) error {
 	}
 
 	bucket := c.String("bucket")
-	storage, err := storage.New(bucket)
+	store, err := storage.New(bucket, c.String("public-url"), c.String("storage-signing-key"))
 	if err != nil {
-		log.WithField("bucket", bucket).WithError(err).Error("could not connect to S3")
+		log.WithField("bucket", bucket).WithError(err).Error("could not set up record storage")
 		return err
 	}
+	if _, ok := store.(*storage.LocalStorage); ok && c.String("storage-signing-key") == "" {
+		log.Warn("no storage signing key is set, so record URLs will stop working when imaged restarts")
+	}
 
-	worker, err := worker.New(worker.Config{
-		TemplatesPath:      c.String("templates-path"),
-		TemplatesURL:       c.String("templates-url"),
-		Packer:             c.String("packer"),
-		AnsibleSecretsFile: c.String("secrets"),
-		DB:                 db,
-		Storage:            storage,
+	webhooks, err := webhook.New(webhook.Config{
+		URLs:         c.StringSlice("webhook-url"),
+		Secret:       c.String("webhook-secret"),
+		DB:           db,
+		PollInterval: c.Duration("poll-interval"),
 	})
 	if err != nil {
-		log.WithError(err).Error("could not create worker")
+		log.WithError(err).Error("could not set up webhooks")
 		return err
 	}
 
-	go worker.Run()
-	log.Debug("started worker")
+	secretsConfig := &secrets.Config{}
+	if path := c.String("secrets-config"); path != "" {
+		if secretsConfig, err = secrets.Load(path); err != nil {
+			log.WithField("path", path).WithError(err).Error("could not load secrets config")
+			return err
+		}
+	}
+	if path := c.String("secrets"); path != "" {
+		secretsConfig.Rules = append(secretsConfig.Rules, secrets.AnsibleSecretsFile(path))
+	}
 
-	server := &server.Server{
-		DB:      db,
-		Storage: storage,
-		Worker:  worker,
+	workers, err := worker.NewPool(worker.Config{
+		TemplatesPath: c.String("templates-path"),
+		TemplatesURL:  c.String("templates-url"),
+		TemplatesAuth: &templates.Auth{
+			SSHKeyFile:       c.String("templates-ssh-key"),
+			SSHKeyPassphrase: c.String("templates-ssh-key-passphrase"),
+			KnownHostsFile:   c.String("templates-known-hosts"),
+			Username:         c.String("templates-username"),
+			Password:         c.String("templates-password"),
+			Hosts:            c.StringSlice("templates-credential-host"),
+		},
+		Packer:            c.String("packer"),
+		Secrets:           secretsConfig,
+		DB:                db,
+		Storage:           store,
+		Webhooks:          webhooks,
+		PollInterval:      c.Duration("poll-interval"),
+		OrphanPolicy:      worker.OrphanPolicy(c.String("orphan-policy")),
+		OrphanTimeout:     c.Duration("orphan-timeout"),
+		InstanceID:        c.String("instance-id"),
+		BuildTimeout:      c.Duration("build-timeout"),
+		MaxAttempts:       c.Int("max-attempts"),
+		ValidateTemplates: c.Bool("validate-templates"),
+		RedactEnv:         c.StringSlice("redact-env"),
+	}, c.Int("workers"))
+	if err != nil {
+		log.WithError(err).Error("could not create workers")
+		return err
+	}
+
+	srv := &server.Server{
+		DB:        db,
+		Storage:   store,
+		Workers:   workers,
+		Templates: workers.Templates(),
 	}
 
 	if c.Bool("migrate") {
@@ -123,11 +362,189 @@ func Run(c *cli.Context) error {
 		log.Debug("skipped database migration")
 	}
 
+	// Wait until the database is migrated so the workers can rely on the queue schema
+	if err = workers.Recover(context.Background()); err != nil {
+		log.WithError(err).Error("could not recover orphaned builds")
+		return err
+	}
+
+	go workers.Run()
+	log.WithField("count", c.Int("workers")).Debug("started workers")
+
+	if interval := c.Duration("templates-fetch-interval"); interval > 0 {
+		go srv.Templates.Poll(interval)
+		log.WithField("interval", interval).Debug("started fetching templates repo")
+	}
+
+	scheduler := &schedule.Scheduler{
+		DB:      db,
+		Starter: srv,
+	}
+	go scheduler.Run()
+	log.Debug("started scheduler")
+
+	if policy := retentionPolicy(c); policy.Enabled() {
+		collector := &retention.Collector{
+			DB:       db,
+			Storage:  store,
+			Policy:   policy,
+			Interval: c.Duration("retention-interval"),
+			DryRun:   c.Bool("retention-dry-run"),
+		}
+		go collector.Run()
+		log.WithField("dry_run", collector.DryRun).Debug("started record collector")
+	}
+
+	go webhooks.Run()
+	log.WithField("urls", len(c.StringSlice("webhook-url"))).Debug("started webhook dispatcher")
+
 	log.Info("starting RPC server")
-	handler := rpc.NewImagesServer(server, &twirp.ServerHooks{
-		ResponseSent: handleResponseSent,
-	})
-	return http.ListenAndServe(":8080", handler)
+	hooks := &twirp.ServerHooks{
+		RequestReceived: server.RequestReceived,
+		ResponseSent:    handleResponseSent,
+	}
+
+	var handler, records http.Handler
+	if c.Bool("auth") {
+		authenticator := &auth.Authenticator{DB: db}
+		hooks.RequestRouted = authenticator.RequestRouted
+		handler = authenticator.Handler(rpc.NewImagesServer(srv, hooks))
+		records = authenticator.RequireScopes(&server.RecordHandler{Server: srv}, server.RecordScopes)
+	} else {
+		log.Warn("API authentication is disabled")
+		handler = rpc.NewImagesServer(srv, hooks)
+		records = &server.RecordHandler{Server: srv}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(rpc.ImagesPathPrefix, handler)
+	mux.Handle(server.RecordsPath, records)
+	mux.Handle(server.BuildsPath, records)
+	mux.Handle("/metrics", promhttp.Handler())
+
+	if secret := c.String("github-secret"); secret != "" {
+		branches := c.StringSlice("github-branch")
+		if len(branches) == 0 {
+			branches = []string{"master"}
+		}
+
+		mux.Handle(server.PushPath, &server.PushHandler{
+			Server:   srv,
+			Secret:   secret,
+			Branches: branches,
+		})
+		log.WithField("branches", branches).Debug("enabled GitHub push webhook")
+	}
+
+	// Records stored locally are downloaded from imaged itself
+	if local, ok := store.(*storage.LocalStorage); ok {
+		mux.Handle(storage.LocalPathPrefix, local)
+	}
+
+	return http.ListenAndServe(":8080", mux)
+}
+
+// CreateToken creates an API token and prints it.
+//
+// Only a hash of the token is saved, so this is the only time it's shown.
+func CreateToken(c *cli.Context) error {
+	name := c.String("name")
+	if name == "" {
+		return cli.NewExitError("a token name is required", 1)
+	}
+
+	scopes := c.StringSlice("scope")
+	if len(scopes) == 0 {
+		scopes = []string{string(auth.ScopeRead)}
+	}
+	for _, s := range scopes {
+		if _, err := auth.ParseScope(s); err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+	}
+
+	db, err := db.NewConnection(c.GlobalString("database"))
+	if err != nil {
+		log.WithError(err).Error("could not connect to database")
+		return err
+	}
+
+	token, hash, err := auth.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	if _, err = db.CreateToken(context.Background(), name, hash, scopes); err != nil {
+		log.WithError(err).Error("could not save token")
+		return err
+	}
+
+	fmt.Println(token)
+	return nil
+}
+
+// CollectRecords deletes expired records once.
+func CollectRecords(c *cli.Context) error {
+	policy := retentionPolicy(c)
+	if !policy.Enabled() {
+		return cli.NewExitError("no retention max age is set, so no records expire", 1)
+	}
+
+	db, err := db.NewConnection(c.GlobalString("database"))
+	if err != nil {
+		log.WithError(err).Error("could not connect to database")
+		return err
+	}
+
+	store, err := storage.New(c.GlobalString("bucket"), c.GlobalString("public-url"), c.GlobalString("storage-signing-key"))
+	if err != nil {
+		log.WithError(err).Error("could not set up record storage")
+		return err
+	}
+
+	collector := &retention.Collector{
+		DB:      db,
+		Storage: store,
+		Policy:  policy,
+		DryRun:  c.Bool("dry-run") || c.GlobalBool("retention-dry-run"),
+	}
+
+	_, err = collector.Collect(context.Background())
+	return err
+}
+
+// retentionPolicy reads the record retention policy from the global flags.
+func retentionPolicy(c *cli.Context) retention.Policy {
+	return retention.Policy{
+		KeepLast:        c.GlobalInt("retention-keep-builds"),
+		SucceededMaxAge: c.GlobalDuration("retention-max-age"),
+		FailedLogMaxAge: c.GlobalDuration("retention-failed-log-max-age"),
+	}
+}
+
+// RevokeToken deletes an API token.
+func RevokeToken(c *cli.Context) error {
+	name := c.Args().First()
+	if name == "" {
+		return cli.NewExitError("a token name is required", 1)
+	}
+
+	db, err := db.NewConnection(c.GlobalString("database"))
+	if err != nil {
+		log.WithError(err).Error("could not connect to database")
+		return err
+	}
+
+	if err = db.DeleteToken(context.Background(), name); err != nil {
+		if err == sql.ErrNoRows {
+			return cli.NewExitError("no token named "+name, 1)
+		}
+
+		log.WithError(err).Error("could not revoke token")
+		return err
+	}
+
+	return nil
 }
 
 func handleResponseSent(ctx context.Context) {
@@ -137,4 +554,6 @@ func handleResponseSent(ctx context.Context) {
 		"method": method,
 		"code":   status,
 	}).Info("response sent")
+
+	server.ResponseSent(ctx)
 }
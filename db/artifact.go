@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"github.com/lib/pq"
+	pb "github.com/travis-ci/imaged/rpc/images"
+)
+
+// Artifact represents something a Packer builder produced during a build,
+// like a VM template in vSphere.
+type Artifact struct {
+	ID          int64
+	BuildID     int64 `db:"build_id"`
+	Builder     string
+	BuilderID   string `db:"builder_id"`
+	ArtifactID  string `db:"artifact_id"`
+	Description string
+	Files       pq.StringArray
+}
+
+// Message converts the artifact into a protobuf message.
+func (a *Artifact) Message() *pb.Artifact {
+	return &pb.Artifact{
+		Id:          a.ID,
+		BuildId:     a.BuildID,
+		Builder:     a.Builder,
+		BuilderId:   a.BuilderID,
+		ArtifactId:  a.ArtifactID,
+		Description: a.Description,
+		Files:       a.Files,
+	}
+}
+
+// CreateArtifact records an artifact that a build produced.
+func (db *Connection) CreateArtifact(ctx context.Context, build *Build, a *Artifact) (*Artifact, error) {
+	files := a.Files
+	if files == nil {
+		files = pq.StringArray{}
+	}
+
+	var id int64
+	if err := db.QueryRowContext(ctx, "INSERT INTO artifacts (build_id, builder, builder_id, artifact_id, description, files) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id", build.ID, a.Builder, a.BuilderID, a.ArtifactID, a.Description, files).Scan(&id); err != nil {
+		return nil, err
+	}
+
+	return &Artifact{
+		ID:          id,
+		BuildID:     build.ID,
+		Builder:     a.Builder,
+		BuilderID:   a.BuilderID,
+		ArtifactID:  a.ArtifactID,
+		Description: a.Description,
+		Files:       files,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/pkg/errors"
+	pb "github.com/travis-ci/imaged/rpc/images"
+	"strconv"
+	"time"
+)
+
+// Attempt represents a single run of a build.
+//
+// A build that fails may be retried, so it can have more than one attempt,
+// each with its own build log and records.
+type Attempt struct {
+	ID            int64
+	BuildID       int64 `db:"build_id"`
+	Number        int
+	Status        BuildStatus
+	StartedAt     time.Time  `db:"started_at"`
+	FinishedAt    *time.Time `db:"finished_at"`
+	FailureReason *string    `db:"failure_reason"`
+}
+
+// Message converts the attempt into a protobuf message.
+func (a *Attempt) Message() *pb.Attempt {
+	var failureReason string
+	if a.FailureReason != nil {
+		failureReason = *a.FailureReason
+	}
+
+	var finish int64
+	if a.FinishedAt != nil {
+		finish = a.FinishedAt.Unix()
+	}
+
+	return &pb.Attempt{
+		Id:            a.ID,
+		BuildId:       a.BuildID,
+		Number:        int32(a.Number),
+		Status:        a.Status.Enum(),
+		StartedAt:     a.StartedAt.Unix(),
+		FinishedAt:    finish,
+		FailureReason: failureReason,
+	}
+}
+
+// RecordKey generates a storage key for storing a build record for this attempt.
+func (a *Attempt) RecordKey(filename string) string {
+	return "records/" + strconv.FormatInt(a.BuildID, 10) + "/attempts/" + strconv.Itoa(a.Number) + "/" + filename
+}
+
+// CreateAttempt records the start of a new attempt of a build, numbered after
+// the build's previous attempts.
+func (db *Connection) CreateAttempt(ctx context.Context, b *Build) (*Attempt, error) {
+	var attempt Attempt
+	if err := db.GetContext(ctx, &attempt, "INSERT INTO attempts (build_id, number) SELECT $1, COALESCE(MAX(number), 0) + 1 FROM attempts WHERE build_id = $1 RETURNING *", b.ID); err != nil {
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+// LastAttempt returns the most recent attempt of a build, or nil if it hasn't
+// been attempted.
+func (db *Connection) LastAttempt(ctx context.Context, b *Build) (*Attempt, error) {
+	var attempt Attempt
+	if err := db.GetContext(ctx, &attempt, "SELECT * FROM attempts WHERE build_id = $1 ORDER BY number DESC LIMIT 1", b.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+// FinishAttempt saves the status of an attempt that has finished.
+func (db *Connection) FinishAttempt(ctx context.Context, a *Attempt) error {
+	switch a.Status {
+	default:
+		return errors.New("attempt must be either succeeded, failed, cancelled or timed out to be finished")
+	case BuildStatusSucceeded, BuildStatusFailed, BuildStatusCancelled, BuildStatusTimedOut:
+		return db.GetContext(ctx, a, "UPDATE attempts SET status = $2, failure_reason = $3, finished_at = now() WHERE id = $1 RETURNING *", a.ID, a.Status, a.FailureReason)
+	}
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	pb "github.com/travis-ci/imaged/rpc/images"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditEvent records a call to an API method that changes something.
+type AuditEvent struct {
+	ID        int64
+	Principal string
+	Method    string
+	Summary   string
+	Code      string
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Message converts the audit event into a protobuf message.
+func (e *AuditEvent) Message() *pb.AuditEvent {
+	return &pb.AuditEvent{
+		Id:        e.ID,
+		Principal: e.Principal,
+		Method:    e.Method,
+		Summary:   e.Summary,
+		Code:      e.Code,
+		CreatedAt: e.CreatedAt.Unix(),
+	}
+}
+
+// AuditEventFilter chooses which audit events ListAuditEvents returns.
+//
+// Empty fields match any event.
+type AuditEventFilter struct {
+	Principal     string
+	Method        string
+	Code          string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+
+	// BeforeID only matches events older than the event with this ID, for
+	// paging through the events.
+	BeforeID int64
+	// Limit is the most events to return.
+	Limit int
+}
+
+// CreateAuditEvent saves a new audit event.
+func (db *Connection) CreateAuditEvent(ctx context.Context, e *AuditEvent) error {
+	_, err := db.ExecContext(ctx, "INSERT INTO audit_events (principal, method, summary, code) VALUES ($1, $2, $3, $4)", e.Principal, e.Method, e.Summary, e.Code)
+	return err
+}
+
+// ListAuditEvents gets the most recent audit events that match a filter,
+// newest first.
+func (db *Connection) ListAuditEvents(ctx context.Context, f AuditEventFilter) ([]AuditEvent, error) {
+	q, args := listAuditEventsQuery(f)
+
+	var events []AuditEvent
+	if err := db.SelectContext(ctx, &events, q, args...); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// listAuditEventsQuery builds the SQL query and its arguments for
+// ListAuditEvents.
+func listAuditEventsQuery(f AuditEventFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	where := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, strings.Replace(cond, "?", "$"+strconv.Itoa(len(args)), 1))
+	}
+
+	if f.Principal != "" {
+		where("principal = ?", f.Principal)
+	}
+	if f.Method != "" {
+		where("method = ?", f.Method)
+	}
+	if f.Code != "" {
+		where("code = ?", f.Code)
+	}
+	if f.CreatedAfter != nil {
+		where("created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		where("created_at < ?", *f.CreatedBefore)
+	}
+	if f.BeforeID != 0 {
+		where("id < ?", f.BeforeID)
+	}
+
+	q := "SELECT * FROM audit_events"
+	if len(conds) > 0 {
+		q += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	args = append(args, f.Limit)
+	q += " ORDER BY id DESC LIMIT $" + strconv.Itoa(len(args))
+
+	return q, args
+}
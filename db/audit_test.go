@@ -0,0 +1,58 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListAuditEventsQuery(t *testing.T) {
+	after := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	q, args := listAuditEventsQuery(AuditEventFilter{Limit: 50})
+	if want := "SELECT * FROM audit_events ORDER BY id DESC LIMIT $1"; q != want {
+		t.Errorf("query with no filter = %q, want %q", q, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{50}) {
+		t.Errorf("args with no filter = %#v, want the limit", args)
+	}
+
+	q, args = listAuditEventsQuery(AuditEventFilter{
+		Principal:     "deploy",
+		Method:        "StartBuild",
+		Code:          "ok",
+		CreatedAfter:  &after,
+		CreatedBefore: &before,
+		BeforeID:      7,
+		Limit:         100,
+	})
+
+	want := "SELECT * FROM audit_events WHERE principal = $1 AND method = $2 AND code = $3 AND created_at >= $4 AND created_at < $5 AND id < $6 ORDER BY id DESC LIMIT $7"
+	if q != want {
+		t.Errorf("query with every filter = %q, want %q", q, want)
+	}
+	if wantArgs := []interface{}{"deploy", "StartBuild", "ok", after, before, int64(7), 100}; !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args with every filter = %#v, want %#v", args, wantArgs)
+	}
+
+	// the placeholders are numbered by the filters that are set, so a single
+	// filter is always $1 and the limit is always $2
+	for _, f := range []AuditEventFilter{
+		{Principal: "deploy"},
+		{Method: "CancelBuild"},
+		{Code: "permission_denied"},
+		{CreatedAfter: &after},
+		{CreatedBefore: &before},
+		{BeforeID: 7},
+	} {
+		q, args := listAuditEventsQuery(f)
+		if cond := strings.Split(q, " ORDER BY")[0]; strings.Count(cond, "$") != 1 || !strings.HasSuffix(cond, " $1") {
+			t.Errorf("query for %+v = %q, want one condition on $1", f, q)
+		}
+		if len(args) != 2 || !strings.HasSuffix(q, "LIMIT $2") {
+			t.Errorf("query for %+v = %q with args %#v, want the limit as $2", f, q, args)
+		}
+	}
+}
@@ -2,10 +2,15 @@ package db
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	pb "github.com/travis-ci/imaged/rpc/images"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,70 +25,203 @@ type BuildStatus string
 // different value from the DB.
 const (
 	BuildStatusCreated   BuildStatus = "created"
+	BuildStatusQueued    BuildStatus = "queued"
 	BuildStatusStarted   BuildStatus = "started"
 	BuildStatusSucceeded BuildStatus = "succeeded"
 	BuildStatusFailed    BuildStatus = "failed"
+	BuildStatusCancelled BuildStatus = "cancelled"
+	BuildStatusTimedOut  BuildStatus = "timed_out"
 )
 
 // Build represents a Packer build that a user requested to run.
 type Build struct {
-	ID           int64
-	Name         string
-	Revision     string
-	FullRevision *string `db:"full_revision"`
-	Status       BuildStatus
-	CreatedAt    time.Time  `db:"created_at"`
-	StartedAt    *time.Time `db:"started_at"`
-	FinishedAt   *time.Time `db:"finished_at"`
-	Records      []Record
+	ID              int64
+	Name            string
+	Revision        string
+	FullRevision    *string `db:"full_revision"`
+	Status          BuildStatus
+	CreatedAt       time.Time  `db:"created_at"`
+	StartedAt       *time.Time `db:"started_at"`
+	FinishedAt      *time.Time `db:"finished_at"`
+	FailureReason   *string    `db:"failure_reason"`
+	Variables       Variables
+	VarFiles        pq.StringArray `db:"var_files"`
+	TimeoutSeconds  *int64         `db:"timeout_seconds"`
+	RetriedFrom     *int64         `db:"retried_from"`
+	Principal       *string        `db:"principal"`
+	Released        bool
+	ClaimedBy       *string    `db:"claimed_by"`
+	HeartbeatAt     *time.Time `db:"heartbeat_at"`
+	CancelRequested bool       `db:"cancel_requested"`
+	Records         []Record
+	Artifacts       []Artifact
+	Attempts        []Attempt
 }
 
+// Variables are the Packer user variables that are set for a build.
+type Variables map[string]string
+
 // Message converts the build into a protobuf message.
 func (b *Build) Message() *pb.Build {
-	var fullRevision string
+	var fullRevision, failureReason, principal string
 	if b.FullRevision != nil {
 		fullRevision = *b.FullRevision
 	}
+	if b.FailureReason != nil {
+		failureReason = *b.FailureReason
+	}
+	if b.Principal != nil {
+		principal = *b.Principal
+	}
 
-	var start, finish int64
+	var start, finish, timeout, retriedFrom int64
 	if b.StartedAt != nil {
 		start = b.StartedAt.Unix()
 	}
 	if b.FinishedAt != nil {
 		finish = b.FinishedAt.Unix()
 	}
+	if b.TimeoutSeconds != nil {
+		timeout = *b.TimeoutSeconds
+	}
+	if b.RetriedFrom != nil {
+		retriedFrom = *b.RetriedFrom
+	}
 
 	msg := &pb.Build{
-		Id:           b.ID,
-		Name:         b.Name,
-		Revision:     b.Revision,
-		FullRevision: fullRevision,
-		Status:       b.Status.Enum(),
-		CreatedAt:    b.CreatedAt.Unix(),
-		StartedAt:    start,
-		FinishedAt:   finish,
+		Id:             b.ID,
+		Name:           b.Name,
+		Revision:       b.Revision,
+		FullRevision:   fullRevision,
+		Status:         b.Status.Enum(),
+		CreatedAt:      b.CreatedAt.Unix(),
+		StartedAt:      start,
+		FinishedAt:     finish,
+		FailureReason:  failureReason,
+		Variables:      b.Variables,
+		VarFiles:       b.VarFiles,
+		TimeoutSeconds: timeout,
+		RetriedFrom:    retriedFrom,
+		Principal:      principal,
+		Released:       b.Released,
 	}
 	for _, r := range b.Records {
 		msg.Records = append(msg.Records, r.Message())
 	}
+	for _, a := range b.Artifacts {
+		msg.Artifacts = append(msg.Artifacts, a.Message())
+	}
+	for _, a := range b.Attempts {
+		msg.Attempts = append(msg.Attempts, a.Message())
+	}
 	return msg
 }
 
-// RecordKey generates an S3 key for storing a build record for this build.
+// RecordKey generates a storage key for storing a build record for this build.
 func (b *Build) RecordKey(filename string) string {
 	return "records/" + strconv.FormatInt(b.ID, 10) + "/" + filename
 }
 
-// RecentBuilds gets a list of the most recent builds.
-func (db *Connection) RecentBuilds(ctx context.Context) ([]Build, error) {
+// AttachedRecordKey generates a storage key for a record attached to this
+// build after it ran.
+//
+// Every upload gets its own key, so an upload can never overwrite the file of
+// a record that already exists, even if two uploads of the same name race.
+// This relies on the file name being a relative path without .. segments,
+// which the server checks before attaching a record.
+func (b *Build) AttachedRecordKey(filename string) (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", errors.Wrap(err, "could not generate record key")
+	}
+
+	return "records/" + strconv.FormatInt(b.ID, 10) + "/attached/" + hex.EncodeToString(id) + "/" + filename, nil
+}
+
+// BuildFilter narrows down the builds returned by ListBuilds.
+//
+// Zero values are ignored, so an empty filter matches every build.
+type BuildFilter struct {
+	Name           string
+	Statuses       []BuildStatus
+	Revision       string
+	FullRevision   string
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	FinishedAfter  *time.Time
+	FinishedBefore *time.Time
+
+	// BeforeID only matches builds older than the build with this ID, for
+	// paging through the builds.
+	BeforeID int64
+	// Limit is the most builds to return.
+	Limit int
+}
+
+// ListBuilds gets the most recent builds that match a filter, newest first.
+func (db *Connection) ListBuilds(ctx context.Context, f BuildFilter) ([]Build, error) {
+	q, args := listBuildsQuery(f)
+
 	var builds []Build
-	if err := db.Select(&builds, "SELECT * FROM builds ORDER BY id DESC LIMIT 20"); err != nil {
+	if err := db.SelectContext(ctx, &builds, q, args...); err != nil {
 		return nil, err
 	}
 
 	return builds, nil
 }
 
+// listBuildsQuery builds the SQL query and its arguments for ListBuilds.
+func listBuildsQuery(f BuildFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	where := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, strings.Replace(cond, "?", "$"+strconv.Itoa(len(args)), 1))
+	}
+
+	if f.Name != "" {
+		where("name = ?", f.Name)
+	}
+	if len(f.Statuses) > 0 {
+		statuses := make(pq.StringArray, len(f.Statuses))
+		for i, s := range f.Statuses {
+			statuses[i] = string(s)
+		}
+		where("status = ANY(?::build_status[])", statuses)
+	}
+	if f.Revision != "" {
+		where("revision = ?", f.Revision)
+	}
+	if f.FullRevision != "" {
+		where("full_revision = ?", f.FullRevision)
+	}
+	if f.CreatedAfter != nil {
+		where("created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		where("created_at < ?", *f.CreatedBefore)
+	}
+	if f.FinishedAfter != nil {
+		where("finished_at >= ?", *f.FinishedAfter)
+	}
+	if f.FinishedBefore != nil {
+		where("finished_at < ?", *f.FinishedBefore)
+	}
+	if f.BeforeID != 0 {
+		where("id < ?", f.BeforeID)
+	}
+
+	q := "SELECT * FROM builds"
+	if len(conds) > 0 {
+		q += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	args = append(args, f.Limit)
+	q += " ORDER BY id DESC LIMIT $" + strconv.Itoa(len(args))
+
+	return q, args
+}
+
 // GetBuild retrieves a build by ID.
 func (db *Connection) GetBuild(ctx context.Context, id int64) (*Build, error) {
 	var build Build
@@ -94,7 +232,7 @@ func (db *Connection) GetBuild(ctx context.Context, id int64) (*Build, error) {
 	return &build, nil
 }
 
-// GetBuildFull retreives a build by ID, and its attached records.
+// GetBuildFull retreives a build by ID, and its attached records, artifacts and attempts.
 func (db *Connection) GetBuildFull(ctx context.Context, id int64) (*Build, error) {
 	build, err := db.GetBuild(ctx, id)
 	if err != nil {
@@ -105,6 +243,14 @@ func (db *Connection) GetBuildFull(ctx context.Context, id int64) (*Build, error
 		return nil, err
 	}
 
+	if err = db.Select(&build.Artifacts, "SELECT * FROM artifacts WHERE build_id = $1 ORDER BY id", id); err != nil {
+		return nil, err
+	}
+
+	if err = db.Select(&build.Attempts, "SELECT * FROM attempts WHERE build_id = $1 ORDER BY number", id); err != nil {
+		return nil, err
+	}
+
 	return build, nil
 }
 
@@ -123,9 +269,20 @@ func (db *Connection) LastBuild(ctx context.Context, name string) (*Build, error
 }
 
 // CreateBuild records a new build that was just requested.
-func (db *Connection) CreateBuild(ctx context.Context, name string, revision string) (*Build, error) {
+//
+// Only the fields of the build that can be requested are saved, along with
+// the full revision and original build of a retried build and who started
+// the build. The saved build is returned.
+func (db *Connection) CreateBuild(ctx context.Context, b *Build) (*Build, error) {
+	if b.Variables == nil {
+		b.Variables = Variables{}
+	}
+	if b.VarFiles == nil {
+		b.VarFiles = pq.StringArray{}
+	}
+
 	var id int64
-	err := db.QueryRowContext(ctx, "INSERT INTO builds (name, revision) VALUES ($1, $2) RETURNING id", name, revision).Scan(&id)
+	err := db.QueryRowContext(ctx, "INSERT INTO builds (name, revision, variables, var_files, timeout_seconds, full_revision, retried_from, principal) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id", b.Name, b.Revision, b.Variables, b.VarFiles, b.TimeoutSeconds, b.FullRevision, b.RetriedFrom, b.Principal).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
@@ -133,9 +290,12 @@ func (db *Connection) CreateBuild(ctx context.Context, name string, revision str
 	return db.GetBuild(ctx, id)
 }
 
-// StartBuild marks a build as started and updates its started at timestamp.
-func (db *Connection) StartBuild(ctx context.Context, b *Build) error {
-	if _, err := db.ExecContext(ctx, "UPDATE builds SET status = 'started', started_at = now() WHERE id = $1", b.ID); err != nil {
+// QueueBuild marks a build as queued so that a worker will pick it up.
+//
+// If the build had already started, it's reset so that it can start again,
+// and it no longer belongs to the instance that claimed it.
+func (db *Connection) QueueBuild(ctx context.Context, b *Build) error {
+	if _, err := db.ExecContext(ctx, "UPDATE builds SET status = 'queued', started_at = NULL, claimed_by = NULL, heartbeat_at = NULL, cancel_requested = false WHERE id = $1", b.ID); err != nil {
 		return err
 	}
 
@@ -148,13 +308,164 @@ func (db *Connection) StartBuild(ctx context.Context, b *Build) error {
 	return nil
 }
 
-// FinishBuild marks a build as passed or failed and updates its finished at timestamp.
+// CountQueuedBuilds counts the builds waiting in the queue.
+func (db *Connection) CountQueuedBuilds(ctx context.Context) (int, error) {
+	var n int
+	if err := db.GetContext(ctx, &n, "SELECT count(*) FROM builds WHERE status = 'queued'"); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// ClaimBuild takes the oldest queued build off of the queue, marks it as
+// started by an imaged instance and updates its started at timestamp.
+//
+// The row is locked while it's claimed, so concurrent workers will never
+// claim the same build. If there are no queued builds, it returns nil.
+func (db *Connection) ClaimBuild(ctx context.Context, owner string) (*Build, error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err = tx.GetContext(ctx, &id, "SELECT id FROM builds WHERE status = 'queued' ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED"); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE builds SET status = 'started', started_at = now(), claimed_by = $2, heartbeat_at = now() WHERE id = $1", id, owner); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return db.GetBuild(ctx, id)
+}
+
+// CancelQueuedBuild marks a build as cancelled if it hasn't been started yet.
+//
+// Returns false if the build was not cancelled because a worker has already
+// claimed it or it has already finished.
+func (db *Connection) CancelQueuedBuild(ctx context.Context, b *Build) (bool, error) {
+	res, err := db.ExecContext(ctx, "UPDATE builds SET status = 'cancelled', finished_at = now() WHERE id = $1 AND status IN ('created', 'queued')", b.ID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	newBuild, err := db.GetBuild(ctx, b.ID)
+	if err != nil {
+		return false, err
+	}
+
+	*b = *newBuild
+	return n > 0, nil
+}
+
+// RequestCancel asks the imaged instance running a build to cancel it, for
+// when the build is running on a different instance.
+//
+// Returns false if the build is not running.
+func (db *Connection) RequestCancel(ctx context.Context, b *Build) (bool, error) {
+	res, err := db.ExecContext(ctx, "UPDATE builds SET cancel_requested = true WHERE id = $1 AND status = 'started'", b.ID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	newBuild, err := db.GetBuild(ctx, b.ID)
+	if err != nil {
+		return false, err
+	}
+
+	*b = *newBuild
+	return n > 0, nil
+}
+
+// Heartbeat records that an imaged instance is still running a build it
+// claimed.
+//
+// Returns true if the build should be cancelled because someone asked
+// another instance to cancel it.
+func (db *Connection) Heartbeat(ctx context.Context, b *Build, owner string) (bool, error) {
+	var cancelRequested bool
+	if err := db.GetContext(ctx, &cancelRequested, "UPDATE builds SET heartbeat_at = now() WHERE id = $1 AND claimed_by = $2 RETURNING cancel_requested", b.ID, owner); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return cancelRequested, nil
+}
+
+// OrphanedBuilds claims the builds that were created or started but never
+// finished, and that no other imaged instance is still working on, oldest
+// first.
+//
+// A started build is orphaned if it was claimed by the given owner, which is
+// left behind when imaged stops while it's running a build, or if its owner
+// hasn't sent a heartbeat within the timeout. A created build is orphaned if
+// it was created longer ago than the timeout without being queued. The
+// builds are claimed for the owner, so that two instances never recover the
+// same build.
+func (db *Connection) OrphanedBuilds(ctx context.Context, owner string, timeout time.Duration) ([]Build, error) {
+	var builds []Build
+	query := `
+		UPDATE builds SET claimed_by = $1, heartbeat_at = now()
+		WHERE id IN (
+			SELECT id FROM builds
+			WHERE (status = 'started' AND (claimed_by = $1 OR claimed_by IS NULL OR heartbeat_at IS NULL OR heartbeat_at < now() - $2::bigint * interval '1 second'))
+				OR (status = 'created' AND created_at < now() - $2::bigint * interval '1 second')
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+	if err := db.SelectContext(ctx, &builds, query, owner, int64(timeout/time.Second)); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(builds, func(i, j int) bool {
+		return builds[i].ID < builds[j].ID
+	})
+	return builds, nil
+}
+
+// SetBuildReleased marks whether a build's image was released.
+func (db *Connection) SetBuildReleased(ctx context.Context, b *Build, released bool) error {
+	if _, err := db.ExecContext(ctx, "UPDATE builds SET released = $2 WHERE id = $1", b.ID, released); err != nil {
+		return err
+	}
+
+	b.Released = released
+	return nil
+}
+
+// FinishBuild marks a build as passed, failed or cancelled and updates its finished at timestamp.
+//
+// The failure reason of the build is saved as well.
 func (db *Connection) FinishBuild(ctx context.Context, b *Build) error {
 	switch b.Status {
 	default:
-		return errors.New("build must be either succeeded or failed to be finished")
-	case BuildStatusSucceeded, BuildStatusFailed:
-		if _, err := db.ExecContext(ctx, "UPDATE builds SET status = $2, finished_at = now() WHERE id = $1", b.ID, b.Status); err != nil {
+		return errors.New("build must be either succeeded, failed, cancelled or timed out to be finished")
+	case BuildStatusSucceeded, BuildStatusFailed, BuildStatusCancelled, BuildStatusTimedOut:
+		if _, err := db.ExecContext(ctx, "UPDATE builds SET status = $2, failure_reason = $3, finished_at = now() WHERE id = $1", b.ID, b.Status, b.FailureReason); err != nil {
 			return err
 		}
 
@@ -191,9 +502,32 @@ func (s BuildStatus) Value() (driver.Value, error) {
 	return string(s), nil
 }
 
+// BuildStatusFromEnum converts a protobuf enum to a status.
+func BuildStatusFromEnum(s pb.Build_Status) BuildStatus {
+	return BuildStatus(strings.ToLower(s.String()))
+}
+
 // Enum converts the status to a protobuf enum.
 func (s BuildStatus) Enum() pb.Build_Status {
 	str := strings.ToUpper(string(s))
 	val := pb.Build_Status_value[str]
 	return pb.Build_Status(val)
 }
+
+// Scan reads variables from a JSON database value.
+func (v *Variables) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.Errorf("cannot scan variables from %T", value)
+	}
+	return json.Unmarshal(bytes, v)
+}
+
+// Value converts the variables to JSON for the sql package.
+func (v Variables) Value() (driver.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
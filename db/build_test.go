@@ -0,0 +1,63 @@
+package db
+
+import (
+	"github.com/lib/pq"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListBuildsQuery(t *testing.T) {
+	after := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter BuildFilter
+		query  string
+		args   []interface{}
+	}{
+		{
+			name:   "no filter",
+			filter: BuildFilter{Limit: 20},
+			query:  "SELECT * FROM builds ORDER BY id DESC LIMIT $1",
+			args:   []interface{}{20},
+		},
+		{
+			name:   "name and statuses",
+			filter: BuildFilter{Name: "linux", Statuses: []BuildStatus{BuildStatusFailed, BuildStatusTimedOut}, Limit: 10},
+			query:  "SELECT * FROM builds WHERE name = $1 AND status = ANY($2::build_status[]) ORDER BY id DESC LIMIT $3",
+			args:   []interface{}{"linux", pq.StringArray{"failed", "timed_out"}, 10},
+		},
+		{
+			name:   "revisions",
+			filter: BuildFilter{Revision: "master", FullRevision: "abc123", Limit: 5},
+			query:  "SELECT * FROM builds WHERE revision = $1 AND full_revision = $2 ORDER BY id DESC LIMIT $3",
+			args:   []interface{}{"master", "abc123", 5},
+		},
+		{
+			name: "times and page",
+			filter: BuildFilter{
+				CreatedAfter:   &after,
+				CreatedBefore:  &before,
+				FinishedAfter:  &after,
+				FinishedBefore: &before,
+				BeforeID:       42,
+				Limit:          100,
+			},
+			query: "SELECT * FROM builds WHERE created_at >= $1 AND created_at < $2 AND finished_at >= $3 AND finished_at < $4 AND id < $5 ORDER BY id DESC LIMIT $6",
+			args:  []interface{}{after, before, after, before, int64(42), 100},
+		},
+	}
+
+	for _, tt := range tests {
+		query, args := listBuildsQuery(tt.filter)
+		if query != tt.query {
+			t.Errorf("%s: query = %q, want %q", tt.name, query, tt.query)
+		}
+
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: args = %#v, want %#v", tt.name, args, tt.args)
+		}
+	}
+}
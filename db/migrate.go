@@ -43,6 +43,234 @@ var migrations = []darwin.Migration{
 				ADD COLUMN finished_at timestamp without time zone;
 		`,
 	},
+	{
+		Version:     4,
+		Description: "Adding queued build status",
+		Script: `
+			ALTER TYPE build_status RENAME TO build_status_old;
+			CREATE TYPE build_status AS ENUM
+				('created','queued','started','succeeded','failed');
+			ALTER TABLE builds
+				ALTER COLUMN status DROP DEFAULT,
+				ALTER COLUMN status TYPE build_status USING status::text::build_status,
+				ALTER COLUMN status SET DEFAULT 'created';
+			DROP TYPE build_status_old;
+			CREATE INDEX builds_queued_idx ON builds (id) WHERE status = 'queued';
+		`,
+	},
+	{
+		Version:     5,
+		Description: "Adding build failure reason",
+		Script: `
+			ALTER TABLE builds
+				ADD COLUMN failure_reason text;
+		`,
+	},
+	{
+		Version:     6,
+		Description: "Adding cancelled build status",
+		Script: `
+			DROP INDEX builds_queued_idx;
+			ALTER TYPE build_status RENAME TO build_status_old;
+			CREATE TYPE build_status AS ENUM
+				('created','queued','started','succeeded','failed','cancelled');
+			ALTER TABLE builds
+				ALTER COLUMN status DROP DEFAULT,
+				ALTER COLUMN status TYPE build_status USING status::text::build_status,
+				ALTER COLUMN status SET DEFAULT 'created';
+			DROP TYPE build_status_old;
+			CREATE INDEX builds_queued_idx ON builds (id) WHERE status = 'queued';
+		`,
+	},
+	{
+		Version:     7,
+		Description: "Adding Packer variables to builds",
+		Script: `
+			ALTER TABLE builds
+				ADD COLUMN variables jsonb NOT NULL DEFAULT '{}',
+				ADD COLUMN var_files text[] NOT NULL DEFAULT '{}';
+		`,
+	},
+	{
+		Version:     8,
+		Description: "Creating artifacts table",
+		Script: `
+			CREATE TABLE artifacts (
+				id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+				build_id bigint NOT NULL REFERENCES builds (id),
+				builder text NOT NULL,
+				builder_id text NOT NULL,
+				artifact_id text NOT NULL,
+				description text NOT NULL DEFAULT '',
+				files text[] NOT NULL DEFAULT '{}'
+			);
+			CREATE INDEX artifacts_build_id_idx ON artifacts (build_id);
+		`,
+	},
+	{
+		Version:     9,
+		Description: "Adding indexes for filtering builds",
+		Script: `
+			CREATE INDEX builds_name_idx ON builds (name, id);
+			CREATE INDEX builds_status_idx ON builds (status, id);
+			CREATE INDEX builds_revision_idx ON builds (revision);
+			CREATE INDEX builds_full_revision_idx ON builds (full_revision);
+			CREATE INDEX builds_created_at_idx ON builds (created_at);
+			CREATE INDEX builds_finished_at_idx ON builds (finished_at);
+		`,
+	},
+	{
+		Version:     10,
+		Description: "Adding build timeouts and timed out build status",
+		Script: `
+			DROP INDEX builds_queued_idx;
+			ALTER TYPE build_status RENAME TO build_status_old;
+			CREATE TYPE build_status AS ENUM
+				('created','queued','started','succeeded','failed','cancelled','timed_out');
+			ALTER TABLE builds
+				ALTER COLUMN status DROP DEFAULT,
+				ALTER COLUMN status TYPE build_status USING status::text::build_status,
+				ALTER COLUMN status SET DEFAULT 'created';
+			DROP TYPE build_status_old;
+			CREATE INDEX builds_queued_idx ON builds (id) WHERE status = 'queued';
+
+			ALTER TABLE builds
+				ADD COLUMN timeout_seconds bigint;
+		`,
+	},
+	{
+		Version:     11,
+		Description: "Creating attempts table and adding build retries",
+		Script: `
+			CREATE TABLE attempts (
+				id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+				build_id bigint NOT NULL REFERENCES builds (id),
+				number integer NOT NULL,
+				status build_status NOT NULL DEFAULT 'started',
+				started_at timestamp without time zone NOT NULL DEFAULT now(),
+				finished_at timestamp without time zone,
+				failure_reason text,
+				UNIQUE (build_id, number)
+			);
+
+			ALTER TABLE records
+				ADD COLUMN attempt_id bigint REFERENCES attempts (id),
+				DROP CONSTRAINT records_build_id_filename_key,
+				ADD UNIQUE (attempt_id, filename);
+			CREATE UNIQUE INDEX records_build_id_filename_idx ON records (build_id, filename)
+				WHERE attempt_id IS NULL;
+
+			ALTER TABLE builds
+				ADD COLUMN retried_from bigint REFERENCES builds (id);
+		`,
+	},
+	{
+		Version:     12,
+		Description: "Creating webhook deliveries table",
+		Script: `
+			CREATE TABLE webhook_deliveries (
+				id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+				build_id bigint NOT NULL REFERENCES builds (id),
+				event text NOT NULL,
+				url text NOT NULL,
+				payload text NOT NULL,
+				status text NOT NULL DEFAULT 'pending',
+				attempts integer NOT NULL DEFAULT 0,
+				next_attempt_at timestamp without time zone NOT NULL DEFAULT now(),
+				response_code integer,
+				last_error text,
+				created_at timestamp without time zone NOT NULL DEFAULT now(),
+				delivered_at timestamp without time zone
+			);
+			CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at, id)
+				WHERE status = 'pending';
+			CREATE INDEX webhook_deliveries_build_id_idx ON webhook_deliveries (build_id);
+		`,
+	},
+	{
+		Version:     13,
+		Description: "Creating schedules table",
+		Script: `
+			CREATE TABLE schedules (
+				id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+				name text NOT NULL,
+				revision text NOT NULL,
+				cron_expression text NOT NULL,
+				next_run_at timestamp with time zone NOT NULL,
+				last_run_at timestamp with time zone,
+				last_build_id bigint REFERENCES builds (id),
+				created_at timestamp with time zone NOT NULL DEFAULT now()
+			);
+			CREATE INDEX schedules_next_run_at_idx ON schedules (next_run_at);
+		`,
+	},
+	{
+		Version:     14,
+		Description: "Creating API tokens table and adding principal to builds",
+		Script: `
+			CREATE TABLE api_tokens (
+				id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+				name text NOT NULL UNIQUE,
+				token_hash text NOT NULL UNIQUE,
+				scopes text[] NOT NULL,
+				created_at timestamp with time zone NOT NULL DEFAULT now()
+			);
+			ALTER TABLE builds ADD COLUMN principal text;
+		`,
+	},
+	{
+		Version:     15,
+		Description: "Creating audit events table",
+		Script: `
+			CREATE TABLE audit_events (
+				id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+				principal text NOT NULL,
+				method text NOT NULL,
+				summary text NOT NULL,
+				code text NOT NULL,
+				created_at timestamp with time zone NOT NULL DEFAULT now()
+			);
+			CREATE INDEX audit_events_principal_idx ON audit_events (principal, id);
+			CREATE INDEX audit_events_method_idx ON audit_events (method, id);
+			CREATE INDEX audit_events_created_at_idx ON audit_events (created_at);
+		`,
+	},
+	{
+		Version:     16,
+		Description: "Adding metadata to records",
+		Script: `
+			ALTER TABLE records
+				ADD COLUMN sha256 text,
+				ADD COLUMN size bigint,
+				ADD COLUMN content_type text,
+				ADD COLUMN created_at timestamp with time zone;
+			ALTER TABLE records ALTER COLUMN created_at SET DEFAULT now();
+		`,
+	},
+	{
+		Version:     17,
+		Description: "Adding released flag to builds",
+		Script: `
+			ALTER TABLE builds ADD COLUMN released boolean NOT NULL DEFAULT false;
+		`,
+	},
+	{
+		Version:     18,
+		Description: "Adding owners of claimed builds",
+		Script: `
+			ALTER TABLE builds
+				ADD COLUMN claimed_by text,
+				ADD COLUMN heartbeat_at timestamp with time zone;
+			CREATE INDEX builds_unfinished_idx ON builds (id) WHERE status IN ('created', 'started');
+		`,
+	},
+	{
+		Version:     19,
+		Description: "Adding cancel requests to builds",
+		Script: `
+			ALTER TABLE builds ADD COLUMN cancel_requested boolean NOT NULL DEFAULT false;
+		`,
+	},
 }
 
 // Migrate runs any necessary migrations against the database.
@@ -2,25 +2,69 @@ package db
 
 import (
 	"context"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
 	pb "github.com/travis-ci/imaged/rpc/images"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// BuildLogFileName is the file name of the record that holds a build's log.
+const BuildLogFileName = "build.log"
+
+// uniqueViolation is the Postgres error code for a row that breaks a unique
+// constraint.
+const uniqueViolation = "23505"
+
 // Record represents a build artifact or other file produced during a
 // Packer build that should be kept for reference later.
 type Record struct {
-	ID       int64
-	BuildID  int64 `db:"build_id"`
-	FileName string
-	S3Key    string `db:"s3_key"`
+	ID        int64
+	BuildID   int64  `db:"build_id"`
+	AttemptID *int64 `db:"attempt_id"`
+	FileName  string
+	S3Key     string `db:"s3_key"`
+
+	// These are only missing for records that were created before imaged
+	// saved them.
+	SHA256      *string    `db:"sha256"`
+	Size        *int64     `db:"size"`
+	ContentType *string    `db:"content_type"`
+	CreatedAt   *time.Time `db:"created_at"`
 }
 
 // Message converts the record into a protobuf message.
 func (r *Record) Message() *pb.Record {
+	var attemptID, size, createdAt int64
+	if r.AttemptID != nil {
+		attemptID = *r.AttemptID
+	}
+	if r.Size != nil {
+		size = *r.Size
+	}
+	if r.CreatedAt != nil {
+		createdAt = r.CreatedAt.Unix()
+	}
+
+	var sha256, contentType string
+	if r.SHA256 != nil {
+		sha256 = *r.SHA256
+	}
+	if r.ContentType != nil {
+		contentType = *r.ContentType
+	}
+
 	return &pb.Record{
-		Id:       r.ID,
-		BuildId:  r.BuildID,
-		FileName: r.FileName,
-		S3Key:    r.S3Key,
+		Id:          r.ID,
+		BuildId:     r.BuildID,
+		FileName:    r.FileName,
+		S3Key:       r.S3Key,
+		AttemptId:   attemptID,
+		Sha256:      sha256,
+		Size:        size,
+		ContentType: contentType,
+		CreatedAt:   createdAt,
 	}
 }
 
@@ -35,26 +79,123 @@ func (db *Connection) GetRecord(ctx context.Context, id int64) (*Record, error)
 }
 
 // GetRecordNamed retrieves a build record by file name and build ID.
+//
+// If the build was attempted more than once, the record from the latest
+// attempt is returned.
 func (db *Connection) GetRecordNamed(ctx context.Context, buildID int64, fileName string) (*Record, error) {
 	var record Record
-	if err := db.Get(&record, "SELECT * FROM records WHERE build_id = $1 AND filename = $2", buildID, fileName); err != nil {
+	if err := db.Get(&record, "SELECT * FROM records WHERE build_id = $1 AND filename = $2 ORDER BY id DESC LIMIT 1", buildID, fileName); err != nil {
 		return nil, err
 	}
 
 	return &record, nil
 }
 
-// CreateRecord records a new build record that has already been uploaded to S3.
-func (db *Connection) CreateRecord(ctx context.Context, build *Build, filename string, s3key string) (*Record, error) {
-	var id int64
-	if err := db.QueryRowContext(ctx, "INSERT INTO records (build_id, filename, s3_key) VALUES ($1, $2, $3) RETURNING id", build.ID, filename, s3key).Scan(&id); err != nil {
+// ExpiredRecords finds records that a retention policy says can be deleted,
+// oldest first.
+//
+// Records are kept for the keepLast most recent builds of each template, for
+// builds that haven't finished and for released builds. Other records of
+// succeeded builds expire once the build finished longer ago than
+// succeededMaxAge. Builds that didn't succeed only have their build logs
+// expire, once the build finished longer ago than failedLogMaxAge, and their
+// other records are kept. A max age of zero keeps records forever.
+//
+// At most limit records with IDs after afterID are returned, for paging
+// through them.
+func (db *Connection) ExpiredRecords(ctx context.Context, keepLast int, succeededMaxAge time.Duration, failedLogMaxAge time.Duration, afterID int64, limit int) ([]Record, error) {
+	q, args := expiredRecordsQuery(keepLast, succeededMaxAge, failedLogMaxAge, afterID, limit)
+
+	var records []Record
+	if err := db.SelectContext(ctx, &records, q, args...); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// expiredRecordsQuery builds the SQL query and its arguments for
+// ExpiredRecords. Only the max ages that are set add a rule for expiring
+// records, so with neither set, nothing expires.
+func expiredRecordsQuery(keepLast int, succeededMaxAge time.Duration, failedLogMaxAge time.Duration, afterID int64, limit int) (string, []interface{}) {
+	args := []interface{}{afterID, keepLast}
+	arg := func(a interface{}) string {
+		args = append(args, a)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	var rules []string
+	if succeededMaxAge > 0 {
+		rules = append(rules, "(b.status = 'succeeded' AND b.finished_at < now() - "+arg(int64(succeededMaxAge.Seconds()))+"::bigint * interval '1 second')")
+	}
+	if failedLogMaxAge > 0 {
+		rules = append(rules, "(b.status <> 'succeeded' AND r.filename = "+arg(BuildLogFileName)+" AND b.finished_at < now() - "+arg(int64(failedLogMaxAge.Seconds()))+"::bigint * interval '1 second')")
+	}
+
+	if len(rules) == 0 {
+		rules = append(rules, "false")
+	}
+
+	q := `
+		SELECT r.* FROM records r
+		JOIN builds b ON b.id = r.build_id
+		WHERE r.id > $1
+			AND b.finished_at IS NOT NULL
+			AND NOT b.released
+			AND b.id NOT IN (
+				SELECT id FROM (
+					SELECT id, row_number() OVER (PARTITION BY name ORDER BY id DESC) AS n FROM builds
+				) ranked WHERE n <= $2
+			)
+			AND (` + strings.Join(rules, " OR ") + `)
+		ORDER BY r.id
+		LIMIT ` + arg(limit)
+
+	return q, args
+}
+
+// ErrRecordExists is returned when a record is attached to a build that
+// already has an attached record with the same file name.
+var ErrRecordExists = errors.New("record already exists")
+
+// AttachedRecordExists checks if a build already has a record with a file name
+// that was attached to it after it ran.
+func (db *Connection) AttachedRecordExists(ctx context.Context, build *Build, fileName string) (bool, error) {
+	var exists bool
+	if err := db.GetContext(ctx, &exists, "SELECT EXISTS (SELECT 1 FROM records WHERE build_id = $1 AND filename = $2 AND attempt_id IS NULL)", build.ID, fileName); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// DeleteRecord deletes a record. The file should be deleted from storage
+// first.
+func (db *Connection) DeleteRecord(ctx context.Context, r *Record) error {
+	_, err := db.ExecContext(ctx, "DELETE FROM records WHERE id = $1", r.ID)
+	return err
+}
+
+// CreateRecord records a new build record that has already been uploaded to storage.
+//
+// The file name, key and metadata of the record are saved. The attempt that
+// produced the record may be nil, like when a record is attached to the build
+// later. ErrRecordExists is returned if the build already has a record with the
+// same name from the same attempt.
+func (db *Connection) CreateRecord(ctx context.Context, build *Build, attempt *Attempt, r *Record) (*Record, error) {
+	var attemptID *int64
+	if attempt != nil {
+		attemptID = &attempt.ID
+	}
+
+	var record Record
+	if err := db.GetContext(ctx, &record, "INSERT INTO records (build_id, attempt_id, filename, s3_key, sha256, size, content_type) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *", build.ID, attemptID, r.FileName, r.S3Key, r.SHA256, r.Size, r.ContentType); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolation {
+			return nil, ErrRecordExists
+		}
+
 		return nil, err
 	}
 
-	return &Record{
-		ID:       id,
-		BuildID:  build.ID,
-		FileName: filename,
-		S3Key:    s3key,
-	}, nil
+	return &record, nil
 }
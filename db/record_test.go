@@ -0,0 +1,57 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpiredRecordsQuery(t *testing.T) {
+	const day = 24 * time.Hour
+
+	// rules returns the conditions that make a record expire
+	rules := func(q string) []string {
+		i := strings.Index(q, "AND ((")
+		j := strings.LastIndex(q, "))")
+		if i == -1 || j == -1 {
+			return nil
+		}
+		return strings.Split(q[i+len("AND ("):j+1], " OR ")
+	}
+
+	// Only build logs of failed builds expire, so a failed build's artifacts
+	// are kept even after its log is deleted
+	q, args := expiredRecordsQuery(10, 0, 30*day, 42, 100)
+	r := rules(q)
+	if len(r) != 1 || !strings.Contains(r[0], "b.status <> 'succeeded'") || !strings.Contains(r[0], "r.filename = $3") {
+		t.Errorf("rules for failed builds = %q, want a single rule for the build log of builds that didn't succeed", r)
+	}
+	if want := []interface{}{int64(42), 10, BuildLogFileName, int64(30 * 24 * 60 * 60), 100}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args for failed builds = %#v, want %#v", args, want)
+	}
+
+	// Every record of a succeeded build expires
+	q, args = expiredRecordsQuery(10, 90*day, 0, 0, 100)
+	r = rules(q)
+	if len(r) != 1 || strings.Contains(r[0], "r.filename") || !strings.Contains(r[0], "b.status = 'succeeded'") {
+		t.Errorf("rules for succeeded builds = %q, want a single rule for any record of succeeded builds", r)
+	}
+	if want := []interface{}{int64(0), 10, int64(90 * 24 * 60 * 60), 100}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args for succeeded builds = %#v, want %#v", args, want)
+	}
+
+	q, _ = expiredRecordsQuery(10, 90*day, 30*day, 0, 100)
+	if r = rules(q); len(r) != 2 {
+		t.Errorf("rules with both max ages = %q, want one for each", r)
+	}
+	for _, rule := range r {
+		if strings.Contains(rule, "b.status <> 'succeeded'") && !strings.Contains(rule, "r.filename = ") {
+			t.Errorf("rule %q expires records of failed builds that aren't build logs", rule)
+		}
+	}
+
+	if q, _ = expiredRecordsQuery(10, 0, 0, 0, 100); !strings.Contains(q, "AND (false)") {
+		t.Errorf("query with no max ages = %q, which expires records", q)
+	}
+}
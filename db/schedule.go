@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	pb "github.com/travis-ci/imaged/rpc/images"
+	"time"
+)
+
+// Schedule represents a template that should be built regularly.
+type Schedule struct {
+	ID             int64
+	Name           string
+	Revision       string
+	CronExpression string     `db:"cron_expression"`
+	NextRunAt      time.Time  `db:"next_run_at"`
+	LastRunAt      *time.Time `db:"last_run_at"`
+	LastBuildID    *int64     `db:"last_build_id"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+// Message converts the schedule into a protobuf message.
+func (s *Schedule) Message() *pb.Schedule {
+	var lastRun, lastBuildID int64
+	if s.LastRunAt != nil {
+		lastRun = s.LastRunAt.Unix()
+	}
+	if s.LastBuildID != nil {
+		lastBuildID = *s.LastBuildID
+	}
+
+	return &pb.Schedule{
+		Id:             s.ID,
+		Name:           s.Name,
+		Revision:       s.Revision,
+		CronExpression: s.CronExpression,
+		NextRunAt:      s.NextRunAt.Unix(),
+		LastRunAt:      lastRun,
+		LastBuildId:    lastBuildID,
+		CreatedAt:      s.CreatedAt.Unix(),
+	}
+}
+
+// ListSchedules gets all of the schedules, oldest first.
+func (db *Connection) ListSchedules(ctx context.Context) ([]Schedule, error) {
+	var schedules []Schedule
+	if err := db.SelectContext(ctx, &schedules, "SELECT * FROM schedules ORDER BY id"); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// CreateSchedule saves a new schedule, which will first run at its next run
+// time.
+func (db *Connection) CreateSchedule(ctx context.Context, s *Schedule) (*Schedule, error) {
+	var schedule Schedule
+	if err := db.GetContext(ctx, &schedule, "INSERT INTO schedules (name, revision, cron_expression, next_run_at) VALUES ($1, $2, $3, $4) RETURNING *", s.Name, s.Revision, s.CronExpression, s.NextRunAt); err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// DeleteSchedule deletes a schedule by ID.
+//
+// Returns sql.ErrNoRows if there is no schedule with the ID.
+func (db *Connection) DeleteSchedule(ctx context.Context, id int64) error {
+	res, err := db.ExecContext(ctx, "DELETE FROM schedules WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ClaimDueSchedule takes a schedule that is due to run and moves its next run
+// time forward, so that no other imaged instance runs it too.
+//
+// The next run time is decided by the next function, which is given the
+// schedule. Returns nil if no schedules are due.
+func (db *Connection) ClaimDueSchedule(ctx context.Context, next func(*Schedule) time.Time) (*Schedule, error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var schedule Schedule
+	if err = tx.GetContext(ctx, &schedule, "SELECT * FROM schedules WHERE next_run_at <= now() ORDER BY next_run_at, id LIMIT 1 FOR UPDATE SKIP LOCKED"); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE schedules SET next_run_at = $2, last_run_at = now() WHERE id = $1", schedule.ID, next(&schedule)); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// SetScheduleLastBuild saves the build that a schedule most recently created.
+func (db *Connection) SetScheduleLastBuild(ctx context.Context, s *Schedule, b *Build) error {
+	_, err := db.ExecContext(ctx, "UPDATE schedules SET last_build_id = $2 WHERE id = $1", s.ID, b.ID)
+	return err
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/lib/pq"
+	"time"
+)
+
+// Token is an API token that can be used to authenticate requests.
+//
+// Only a hash of the token itself is stored.
+type Token struct {
+	ID        int64
+	Name      string
+	TokenHash string         `db:"token_hash"`
+	Scopes    pq.StringArray `db:"scopes"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+// CreateToken saves a new API token with a unique name.
+func (db *Connection) CreateToken(ctx context.Context, name string, hash string, scopes []string) (*Token, error) {
+	var token Token
+	if err := db.GetContext(ctx, &token, "INSERT INTO api_tokens (name, token_hash, scopes) VALUES ($1, $2, $3) RETURNING *", name, hash, pq.StringArray(scopes)); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// GetTokenByHash gets the API token with a hash.
+//
+// Returns sql.ErrNoRows if there is no such token.
+func (db *Connection) GetTokenByHash(ctx context.Context, hash string) (*Token, error) {
+	var token Token
+	if err := db.GetContext(ctx, &token, "SELECT * FROM api_tokens WHERE token_hash = $1", hash); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// DeleteToken deletes an API token by name, so it can no longer be used.
+//
+// Returns sql.ErrNoRows if there is no token with the name.
+func (db *Connection) DeleteToken(ctx context.Context, name string) error {
+	res, err := db.ExecContext(ctx, "DELETE FROM api_tokens WHERE name = $1", name)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// DeliveryStatus is the state of a webhook delivery.
+type DeliveryStatus string
+
+// These are the statuses a webhook delivery could have.
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// deliveryLease is how long a claimed delivery is kept from being claimed
+// again, in case the instance that claimed it stops before saving the result.
+const deliveryLease = 5 * time.Minute
+
+// WebhookDelivery is a webhook notification about a build, and the log of
+// trying to deliver it.
+type WebhookDelivery struct {
+	ID            int64
+	BuildID       int64 `db:"build_id"`
+	Event         string
+	URL           string
+	Payload       string
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	ResponseCode  *int       `db:"response_code"`
+	LastError     *string    `db:"last_error"`
+	CreatedAt     time.Time  `db:"created_at"`
+	DeliveredAt   *time.Time `db:"delivered_at"`
+}
+
+// CreateWebhookDelivery saves a webhook notification so that it will be
+// delivered.
+func (db *Connection) CreateWebhookDelivery(ctx context.Context, d *WebhookDelivery) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	if err := db.GetContext(ctx, &delivery, "INSERT INTO webhook_deliveries (build_id, event, url, payload) VALUES ($1, $2, $3, $4) RETURNING *", d.BuildID, d.Event, d.URL, d.Payload); err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// ClaimWebhookDelivery takes the oldest pending webhook delivery that is due
+// to be attempted.
+//
+// The delivery won't be claimed again until it's updated or its lease runs
+// out. Returns nil if no deliveries are due.
+func (db *Connection) ClaimWebhookDelivery(ctx context.Context) (*WebhookDelivery, error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var delivery WebhookDelivery
+	if err = tx.GetContext(ctx, &delivery, "SELECT * FROM webhook_deliveries WHERE status = 'pending' AND next_attempt_at <= now() ORDER BY next_attempt_at, id LIMIT 1 FOR UPDATE SKIP LOCKED"); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE webhook_deliveries SET next_attempt_at = now() + $2 * interval '1 second' WHERE id = $1", delivery.ID, deliveryLease.Seconds()); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// UpdateWebhookDelivery saves the result of an attempt to deliver a webhook.
+//
+// If the delivery is still pending, it will be attempted again after the
+// retry delay.
+func (db *Connection) UpdateWebhookDelivery(ctx context.Context, d *WebhookDelivery, retryDelay time.Duration) error {
+	_, err := db.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET
+			status = $2,
+			attempts = $3,
+			response_code = $4,
+			last_error = $5,
+			next_attempt_at = now() + $6 * interval '1 second',
+			delivered_at = CASE WHEN $2 = 'delivered' THEN now() END
+		WHERE id = $1`, d.ID, d.Status, d.Attempts, d.ResponseCode, d.LastError, retryDelay.Seconds())
+	return err
+}
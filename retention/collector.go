@@ -0,0 +1,137 @@
+// Package retention deletes build records that are no longer needed.
+package retention
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/travis-ci/imaged/db"
+	"github.com/travis-ci/imaged/storage"
+	"time"
+)
+
+const (
+	defaultInterval = time.Hour
+
+	// batchSize is how many expired records are loaded at once.
+	batchSize = 100
+)
+
+// Policy decides how long build records are kept.
+//
+// Records of released builds and of builds that haven't finished are always
+// kept.
+type Policy struct {
+	// KeepLast is how many of the most recent builds of each template keep all
+	// of their records, no matter how old they are.
+	KeepLast int
+	// SucceededMaxAge is how long records of succeeded builds are kept after
+	// the build finished. Zero keeps them forever.
+	SucceededMaxAge time.Duration
+	// FailedLogMaxAge is how long the build logs of failed, cancelled and
+	// timed out builds are kept after the build finished. Zero keeps them
+	// forever. Their other records, like artifacts, are always kept.
+	FailedLogMaxAge time.Duration
+}
+
+// Enabled returns whether the policy ever deletes records.
+func (p Policy) Enabled() bool {
+	return p.SucceededMaxAge > 0 || p.FailedLogMaxAge > 0
+}
+
+// Collector deletes the records that its policy says have expired, removing
+// both the files in storage and their rows in the database.
+type Collector struct {
+	DB      *db.Connection
+	Storage storage.Storage
+	Policy  Policy
+	// Interval is how often the collector looks for expired records.
+	Interval time.Duration
+	// DryRun makes the collector log the records it would delete without
+	// deleting them.
+	DryRun bool
+}
+
+// Run deletes expired records periodically until the process exits.
+//
+// It should be called in a goroutine.
+func (c *Collector) Run() {
+	interval := c.Interval
+	if interval == 0 {
+		interval = defaultInterval
+	}
+
+	for {
+		if _, err := c.Collect(context.Background()); err != nil {
+			log.WithError(err).Error("could not collect expired records")
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// Collect deletes all of the records that have expired, returning how many
+// were deleted, or would have been in a dry run.
+//
+// A record whose file can't be deleted is kept, so it can be tried again
+// next time.
+func (c *Collector) Collect(ctx context.Context) (int, error) {
+	if !c.Policy.Enabled() {
+		return 0, nil
+	}
+
+	var deleted int
+	var afterID int64
+	for {
+		records, err := c.DB.ExpiredRecords(ctx, c.Policy.KeepLast, c.Policy.SucceededMaxAge, c.Policy.FailedLogMaxAge, afterID, batchSize)
+		if err != nil {
+			return deleted, errors.Wrap(err, "could not find expired records")
+		}
+
+		for i := range records {
+			r := &records[i]
+			afterID = r.ID
+
+			if c.delete(ctx, r) {
+				deleted++
+			}
+		}
+
+		if len(records) < batchSize {
+			break
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"count":   deleted,
+		"dry_run": c.DryRun,
+	}).Info("collected expired records")
+	return deleted, nil
+}
+
+func (c *Collector) delete(ctx context.Context, r *db.Record) bool {
+	l := log.WithFields(log.Fields{
+		"build_id":  r.BuildID,
+		"record_id": r.ID,
+		"file_name": r.FileName,
+		"key":       r.S3Key,
+	})
+
+	if c.DryRun {
+		l.Info("would delete expired record")
+		return true
+	}
+
+	if err := c.Storage.Delete(ctx, r.S3Key); err != nil {
+		l.WithError(err).Error("could not delete expired record from storage")
+		return false
+	}
+
+	if err := c.DB.DeleteRecord(ctx, r); err != nil {
+		l.WithError(err).Error("could not delete expired record")
+		return false
+	}
+
+	l.Info("deleted expired record")
+	return true
+}
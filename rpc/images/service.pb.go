@@ -27,6 +27,9 @@ const (
 	Build_STARTED   Build_Status = 1
 	Build_SUCCEEDED Build_Status = 2
 	Build_FAILED    Build_Status = 3
+	Build_QUEUED    Build_Status = 4
+	Build_CANCELLED Build_Status = 5
+	Build_TIMED_OUT Build_Status = 6
 )
 
 var Build_Status_name = map[int32]string{
@@ -34,6 +37,9 @@ var Build_Status_name = map[int32]string{
 	1: "STARTED",
 	2: "SUCCEEDED",
 	3: "FAILED",
+	4: "QUEUED",
+	5: "CANCELLED",
+	6: "TIMED_OUT",
 }
 
 var Build_Status_value = map[string]int32{
@@ -41,6 +47,9 @@ var Build_Status_value = map[string]int32{
 	"STARTED":   1,
 	"SUCCEEDED": 2,
 	"FAILED":    3,
+	"QUEUED":    4,
+	"CANCELLED": 5,
+	"TIMED_OUT": 6,
 }
 
 func (x Build_Status) String() string {
@@ -48,10 +57,30 @@ func (x Build_Status) String() string {
 }
 
 func (Build_Status) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_03e4ae55c7c0e319, []int{14, 0}
+	return fileDescriptor_03e4ae55c7c0e319, []int{38, 0}
 }
 
 type ListBuildsRequest struct {
+	// The most builds to return. Defaults to 20, and can be at most 100.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The next_page_token from a previous response, to get the next page of builds.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Only list builds of this template.
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Only list builds with one of these statuses.
+	Statuses []Build_Status `protobuf:"varint,4,rep,packed,name=statuses,proto3,enum=travisci.images.Build_Status" json:"statuses,omitempty"`
+	// Only list builds that requested this revision.
+	Revision string `protobuf:"bytes,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	// Only list builds that ran at this commit SHA.
+	FullRevision string `protobuf:"bytes,6,opt,name=full_revision,json=fullRevision,proto3" json:"full_revision,omitempty"`
+	// Only list builds created at or after this Unix time.
+	CreatedAfter int64 `protobuf:"varint,7,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	// Only list builds created before this Unix time.
+	CreatedBefore int64 `protobuf:"varint,8,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	// Only list builds that finished at or after this Unix time.
+	FinishedAfter int64 `protobuf:"varint,9,opt,name=finished_after,json=finishedAfter,proto3" json:"finished_after,omitempty"`
+	// Only list builds that finished before this Unix time.
+	FinishedBefore       int64    `protobuf:"varint,10,opt,name=finished_before,json=finishedBefore,proto3" json:"finished_before,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -82,8 +111,81 @@ func (m *ListBuildsRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ListBuildsRequest proto.InternalMessageInfo
 
+func (m *ListBuildsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListBuildsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListBuildsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ListBuildsRequest) GetStatuses() []Build_Status {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
+func (m *ListBuildsRequest) GetRevision() string {
+	if m != nil {
+		return m.Revision
+	}
+	return ""
+}
+
+func (m *ListBuildsRequest) GetFullRevision() string {
+	if m != nil {
+		return m.FullRevision
+	}
+	return ""
+}
+
+func (m *ListBuildsRequest) GetCreatedAfter() int64 {
+	if m != nil {
+		return m.CreatedAfter
+	}
+	return 0
+}
+
+func (m *ListBuildsRequest) GetCreatedBefore() int64 {
+	if m != nil {
+		return m.CreatedBefore
+	}
+	return 0
+}
+
+func (m *ListBuildsRequest) GetFinishedAfter() int64 {
+	if m != nil {
+		return m.FinishedAfter
+	}
+	return 0
+}
+
+func (m *ListBuildsRequest) GetFinishedBefore() int64 {
+	if m != nil {
+		return m.FinishedBefore
+	}
+	return 0
+}
+
 type ListBuildsResponse struct {
-	Builds               []*Build `protobuf:"bytes,1,rep,name=builds,proto3" json:"builds,omitempty"`
+	// The matching builds, newest first.
+	Builds []*Build `protobuf:"bytes,1,rep,name=builds,proto3" json:"builds,omitempty"`
+	// The token to request the next page of builds, or empty if this is the last page.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -121,6 +223,13 @@ func (m *ListBuildsResponse) GetBuilds() []*Build {
 	return nil
 }
 
+func (m *ListBuildsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type GetBuildRequest struct {
 	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -281,7 +390,13 @@ type StartBuildRequest struct {
 	// The name of the Packer template that should be built.
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// The Git revision of the Packer templates repo that should be checked out for the build.
-	Revision             string   `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Revision string `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	// Packer user variables to set for the build.
+	Variables map[string]string `protobuf:"bytes,3,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Paths of Packer var-files in the templates repo to use for the build, relative to its root.
+	VarFiles []string `protobuf:"bytes,4,rep,name=var_files,json=varFiles,proto3" json:"var_files,omitempty"`
+	// How long the build can run before it's stopped, overriding the template's timeout.
+	TimeoutSeconds       int64    `protobuf:"varint,5,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -326,6 +441,27 @@ func (m *StartBuildRequest) GetRevision() string {
 	return ""
 }
 
+func (m *StartBuildRequest) GetVariables() map[string]string {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+func (m *StartBuildRequest) GetVarFiles() []string {
+	if m != nil {
+		return m.VarFiles
+	}
+	return nil
+}
+
+func (m *StartBuildRequest) GetTimeoutSeconds() int64 {
+	if m != nil {
+		return m.TimeoutSeconds
+	}
+	return 0
+}
+
 type StartBuildResponse struct {
 	// The build that was created.
 	Build                *Build   `protobuf:"bytes,1,opt,name=build,proto3" json:"build,omitempty"`
@@ -366,317 +502,1659 @@ func (m *StartBuildResponse) GetBuild() *Build {
 	return nil
 }
 
-type DownloadRecordRequest struct {
-	// The ID of the record to download.
-	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	// The ID of the build for the record.
-	BuildId int64 `protobuf:"varint,2,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
-	// The file name of the record.
-	FileName             string   `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+type CancelBuildRequest struct {
+	// The ID of the build to cancel.
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DownloadRecordRequest) Reset()         { *m = DownloadRecordRequest{} }
-func (m *DownloadRecordRequest) String() string { return proto.CompactTextString(m) }
-func (*DownloadRecordRequest) ProtoMessage()    {}
-func (*DownloadRecordRequest) Descriptor() ([]byte, []int) {
+func (m *CancelBuildRequest) Reset()         { *m = CancelBuildRequest{} }
+func (m *CancelBuildRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelBuildRequest) ProtoMessage()    {}
+func (*CancelBuildRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_03e4ae55c7c0e319, []int{8}
 }
 
-func (m *DownloadRecordRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DownloadRecordRequest.Unmarshal(m, b)
+func (m *CancelBuildRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelBuildRequest.Unmarshal(m, b)
 }
-func (m *DownloadRecordRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DownloadRecordRequest.Marshal(b, m, deterministic)
+func (m *CancelBuildRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelBuildRequest.Marshal(b, m, deterministic)
 }
-func (m *DownloadRecordRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DownloadRecordRequest.Merge(m, src)
+func (m *CancelBuildRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelBuildRequest.Merge(m, src)
 }
-func (m *DownloadRecordRequest) XXX_Size() int {
-	return xxx_messageInfo_DownloadRecordRequest.Size(m)
+func (m *CancelBuildRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelBuildRequest.Size(m)
 }
-func (m *DownloadRecordRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_DownloadRecordRequest.DiscardUnknown(m)
+func (m *CancelBuildRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelBuildRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DownloadRecordRequest proto.InternalMessageInfo
+var xxx_messageInfo_CancelBuildRequest proto.InternalMessageInfo
 
-func (m *DownloadRecordRequest) GetId() int64 {
+func (m *CancelBuildRequest) GetId() int64 {
 	if m != nil {
 		return m.Id
 	}
 	return 0
 }
 
-func (m *DownloadRecordRequest) GetBuildId() int64 {
-	if m != nil {
-		return m.BuildId
-	}
-	return 0
-}
-
-func (m *DownloadRecordRequest) GetFileName() string {
-	if m != nil {
-		return m.FileName
-	}
-	return ""
-}
-
-type DownloadRecordResponse struct {
-	// The contents of the record.
-	Contents             []byte   `protobuf:"bytes,1,opt,name=contents,proto3" json:"contents,omitempty"`
+type CancelBuildResponse struct {
+	// The build that was cancelled.
+	//
+	// A running build keeps the started status until Packer has stopped.
+	Build                *Build   `protobuf:"bytes,1,opt,name=build,proto3" json:"build,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DownloadRecordResponse) Reset()         { *m = DownloadRecordResponse{} }
-func (m *DownloadRecordResponse) String() string { return proto.CompactTextString(m) }
-func (*DownloadRecordResponse) ProtoMessage()    {}
-func (*DownloadRecordResponse) Descriptor() ([]byte, []int) {
+func (m *CancelBuildResponse) Reset()         { *m = CancelBuildResponse{} }
+func (m *CancelBuildResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelBuildResponse) ProtoMessage()    {}
+func (*CancelBuildResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_03e4ae55c7c0e319, []int{9}
 }
 
-func (m *DownloadRecordResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DownloadRecordResponse.Unmarshal(m, b)
+func (m *CancelBuildResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelBuildResponse.Unmarshal(m, b)
 }
-func (m *DownloadRecordResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DownloadRecordResponse.Marshal(b, m, deterministic)
+func (m *CancelBuildResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelBuildResponse.Marshal(b, m, deterministic)
 }
-func (m *DownloadRecordResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DownloadRecordResponse.Merge(m, src)
+func (m *CancelBuildResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelBuildResponse.Merge(m, src)
 }
-func (m *DownloadRecordResponse) XXX_Size() int {
-	return xxx_messageInfo_DownloadRecordResponse.Size(m)
+func (m *CancelBuildResponse) XXX_Size() int {
+	return xxx_messageInfo_CancelBuildResponse.Size(m)
 }
-func (m *DownloadRecordResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_DownloadRecordResponse.DiscardUnknown(m)
+func (m *CancelBuildResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelBuildResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DownloadRecordResponse proto.InternalMessageInfo
+var xxx_messageInfo_CancelBuildResponse proto.InternalMessageInfo
 
-func (m *DownloadRecordResponse) GetContents() []byte {
+func (m *CancelBuildResponse) GetBuild() *Build {
 	if m != nil {
-		return m.Contents
+		return m.Build
 	}
 	return nil
 }
 
-type GetRecordURLRequest struct {
-	// The ID of the record to get a download URL for.
-	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	// The ID of the build for the record.
-	BuildId int64 `protobuf:"varint,2,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
-	// The file name of the record.
-	FileName             string   `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+type RetryBuildRequest struct {
+	// The ID of the finished build to run again.
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetRecordURLRequest) Reset()         { *m = GetRecordURLRequest{} }
-func (m *GetRecordURLRequest) String() string { return proto.CompactTextString(m) }
-func (*GetRecordURLRequest) ProtoMessage()    {}
-func (*GetRecordURLRequest) Descriptor() ([]byte, []int) {
+func (m *RetryBuildRequest) Reset()         { *m = RetryBuildRequest{} }
+func (m *RetryBuildRequest) String() string { return proto.CompactTextString(m) }
+func (*RetryBuildRequest) ProtoMessage()    {}
+func (*RetryBuildRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_03e4ae55c7c0e319, []int{10}
 }
 
-func (m *GetRecordURLRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetRecordURLRequest.Unmarshal(m, b)
+func (m *RetryBuildRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetryBuildRequest.Unmarshal(m, b)
 }
-func (m *GetRecordURLRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetRecordURLRequest.Marshal(b, m, deterministic)
+func (m *RetryBuildRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetryBuildRequest.Marshal(b, m, deterministic)
 }
-func (m *GetRecordURLRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetRecordURLRequest.Merge(m, src)
+func (m *RetryBuildRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetryBuildRequest.Merge(m, src)
 }
-func (m *GetRecordURLRequest) XXX_Size() int {
-	return xxx_messageInfo_GetRecordURLRequest.Size(m)
+func (m *RetryBuildRequest) XXX_Size() int {
+	return xxx_messageInfo_RetryBuildRequest.Size(m)
 }
-func (m *GetRecordURLRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetRecordURLRequest.DiscardUnknown(m)
+func (m *RetryBuildRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetryBuildRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetRecordURLRequest proto.InternalMessageInfo
+var xxx_messageInfo_RetryBuildRequest proto.InternalMessageInfo
 
-func (m *GetRecordURLRequest) GetId() int64 {
+func (m *RetryBuildRequest) GetId() int64 {
 	if m != nil {
 		return m.Id
 	}
 	return 0
 }
 
-func (m *GetRecordURLRequest) GetBuildId() int64 {
-	if m != nil {
-		return m.BuildId
-	}
-	return 0
-}
-
-func (m *GetRecordURLRequest) GetFileName() string {
-	if m != nil {
-		return m.FileName
-	}
-	return ""
-}
-
-type GetRecordURLResponse struct {
-	// The public S3 URL for the build record.
-	Url                  string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+type RetryBuildResponse struct {
+	// The new build, which uses the same template, revision and variables as the original.
+	Build                *Build   `protobuf:"bytes,1,opt,name=build,proto3" json:"build,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetRecordURLResponse) Reset()         { *m = GetRecordURLResponse{} }
-func (m *GetRecordURLResponse) String() string { return proto.CompactTextString(m) }
-func (*GetRecordURLResponse) ProtoMessage()    {}
-func (*GetRecordURLResponse) Descriptor() ([]byte, []int) {
+func (m *RetryBuildResponse) Reset()         { *m = RetryBuildResponse{} }
+func (m *RetryBuildResponse) String() string { return proto.CompactTextString(m) }
+func (*RetryBuildResponse) ProtoMessage()    {}
+func (*RetryBuildResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_03e4ae55c7c0e319, []int{11}
 }
 
-func (m *GetRecordURLResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetRecordURLResponse.Unmarshal(m, b)
+func (m *RetryBuildResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetryBuildResponse.Unmarshal(m, b)
 }
-func (m *GetRecordURLResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetRecordURLResponse.Marshal(b, m, deterministic)
+func (m *RetryBuildResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetryBuildResponse.Marshal(b, m, deterministic)
 }
-func (m *GetRecordURLResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetRecordURLResponse.Merge(m, src)
+func (m *RetryBuildResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetryBuildResponse.Merge(m, src)
 }
-func (m *GetRecordURLResponse) XXX_Size() int {
-	return xxx_messageInfo_GetRecordURLResponse.Size(m)
+func (m *RetryBuildResponse) XXX_Size() int {
+	return xxx_messageInfo_RetryBuildResponse.Size(m)
 }
-func (m *GetRecordURLResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetRecordURLResponse.DiscardUnknown(m)
+func (m *RetryBuildResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetryBuildResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetRecordURLResponse proto.InternalMessageInfo
+var xxx_messageInfo_RetryBuildResponse proto.InternalMessageInfo
 
-func (m *GetRecordURLResponse) GetUrl() string {
+func (m *RetryBuildResponse) GetBuild() *Build {
 	if m != nil {
-		return m.Url
+		return m.Build
 	}
-	return ""
+	return nil
 }
 
-type AttachRecordRequest struct {
-	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	FileName             string   `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
-	Contents             []byte   `protobuf:"bytes,3,opt,name=contents,proto3" json:"contents,omitempty"`
+type SetBuildReleasedRequest struct {
+	// The ID of the build to mark.
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Whether the build's image was released. Records of released builds are never deleted.
+	Released             bool     `protobuf:"varint,2,opt,name=released,proto3" json:"released,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *AttachRecordRequest) Reset()         { *m = AttachRecordRequest{} }
-func (m *AttachRecordRequest) String() string { return proto.CompactTextString(m) }
-func (*AttachRecordRequest) ProtoMessage()    {}
-func (*AttachRecordRequest) Descriptor() ([]byte, []int) {
+func (m *SetBuildReleasedRequest) Reset()         { *m = SetBuildReleasedRequest{} }
+func (m *SetBuildReleasedRequest) String() string { return proto.CompactTextString(m) }
+func (*SetBuildReleasedRequest) ProtoMessage()    {}
+func (*SetBuildReleasedRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_03e4ae55c7c0e319, []int{12}
 }
 
-func (m *AttachRecordRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_AttachRecordRequest.Unmarshal(m, b)
+func (m *SetBuildReleasedRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetBuildReleasedRequest.Unmarshal(m, b)
 }
-func (m *AttachRecordRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_AttachRecordRequest.Marshal(b, m, deterministic)
+func (m *SetBuildReleasedRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetBuildReleasedRequest.Marshal(b, m, deterministic)
 }
-func (m *AttachRecordRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AttachRecordRequest.Merge(m, src)
+func (m *SetBuildReleasedRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetBuildReleasedRequest.Merge(m, src)
 }
-func (m *AttachRecordRequest) XXX_Size() int {
-	return xxx_messageInfo_AttachRecordRequest.Size(m)
+func (m *SetBuildReleasedRequest) XXX_Size() int {
+	return xxx_messageInfo_SetBuildReleasedRequest.Size(m)
 }
-func (m *AttachRecordRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_AttachRecordRequest.DiscardUnknown(m)
+func (m *SetBuildReleasedRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetBuildReleasedRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_AttachRecordRequest proto.InternalMessageInfo
+var xxx_messageInfo_SetBuildReleasedRequest proto.InternalMessageInfo
 
-func (m *AttachRecordRequest) GetId() int64 {
+func (m *SetBuildReleasedRequest) GetId() int64 {
 	if m != nil {
 		return m.Id
 	}
 	return 0
 }
 
-func (m *AttachRecordRequest) GetFileName() string {
-	if m != nil {
-		return m.FileName
-	}
-	return ""
-}
-
-func (m *AttachRecordRequest) GetContents() []byte {
+func (m *SetBuildReleasedRequest) GetReleased() bool {
 	if m != nil {
-		return m.Contents
+		return m.Released
 	}
-	return nil
+	return false
 }
 
-type AttachRecordResponse struct {
-	// The record that was created.
-	Record               *Record  `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+type SetBuildReleasedResponse struct {
+	Build                *Build   `protobuf:"bytes,1,opt,name=build,proto3" json:"build,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *AttachRecordResponse) Reset()         { *m = AttachRecordResponse{} }
-func (m *AttachRecordResponse) String() string { return proto.CompactTextString(m) }
-func (*AttachRecordResponse) ProtoMessage()    {}
-func (*AttachRecordResponse) Descriptor() ([]byte, []int) {
+func (m *SetBuildReleasedResponse) Reset()         { *m = SetBuildReleasedResponse{} }
+func (m *SetBuildReleasedResponse) String() string { return proto.CompactTextString(m) }
+func (*SetBuildReleasedResponse) ProtoMessage()    {}
+func (*SetBuildReleasedResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_03e4ae55c7c0e319, []int{13}
 }
 
-func (m *AttachRecordResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_AttachRecordResponse.Unmarshal(m, b)
+func (m *SetBuildReleasedResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetBuildReleasedResponse.Unmarshal(m, b)
 }
-func (m *AttachRecordResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_AttachRecordResponse.Marshal(b, m, deterministic)
+func (m *SetBuildReleasedResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetBuildReleasedResponse.Marshal(b, m, deterministic)
 }
-func (m *AttachRecordResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AttachRecordResponse.Merge(m, src)
+func (m *SetBuildReleasedResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetBuildReleasedResponse.Merge(m, src)
 }
-func (m *AttachRecordResponse) XXX_Size() int {
-	return xxx_messageInfo_AttachRecordResponse.Size(m)
+func (m *SetBuildReleasedResponse) XXX_Size() int {
+	return xxx_messageInfo_SetBuildReleasedResponse.Size(m)
 }
-func (m *AttachRecordResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_AttachRecordResponse.DiscardUnknown(m)
+func (m *SetBuildReleasedResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetBuildReleasedResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_AttachRecordResponse proto.InternalMessageInfo
+var xxx_messageInfo_SetBuildReleasedResponse proto.InternalMessageInfo
 
-func (m *AttachRecordResponse) GetRecord() *Record {
+func (m *SetBuildReleasedResponse) GetBuild() *Build {
 	if m != nil {
-		return m.Record
+		return m.Build
 	}
 	return nil
 }
 
-type Build struct {
-	Id                   int64        `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Revision             string       `protobuf:"bytes,3,opt,name=revision,proto3" json:"revision,omitempty"`
-	FullRevision         string       `protobuf:"bytes,4,opt,name=full_revision,json=fullRevision,proto3" json:"full_revision,omitempty"`
-	Status               Build_Status `protobuf:"varint,5,opt,name=status,proto3,enum=travisci.images.Build_Status" json:"status,omitempty"`
-	CreatedAt            int64        `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	StartedAt            int64        `protobuf:"varint,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
-	FinishedAt           int64        `protobuf:"varint,8,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
-	Records              []*Record    `protobuf:"bytes,9,rep,name=records,proto3" json:"records,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+type TailBuildLogRequest struct {
+	// The ID of the build whose log should be read.
+	BuildId int64 `protobuf:"varint,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	// The byte offset in the build log to start reading from.
+	Offset               int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TailBuildLogRequest) Reset()         { *m = TailBuildLogRequest{} }
+func (m *TailBuildLogRequest) String() string { return proto.CompactTextString(m) }
+func (*TailBuildLogRequest) ProtoMessage()    {}
+func (*TailBuildLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{14}
+}
+
+func (m *TailBuildLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TailBuildLogRequest.Unmarshal(m, b)
+}
+func (m *TailBuildLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TailBuildLogRequest.Marshal(b, m, deterministic)
+}
+func (m *TailBuildLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailBuildLogRequest.Merge(m, src)
+}
+func (m *TailBuildLogRequest) XXX_Size() int {
+	return xxx_messageInfo_TailBuildLogRequest.Size(m)
+}
+func (m *TailBuildLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailBuildLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TailBuildLogRequest proto.InternalMessageInfo
+
+func (m *TailBuildLogRequest) GetBuildId() int64 {
+	if m != nil {
+		return m.BuildId
+	}
+	return 0
+}
+
+func (m *TailBuildLogRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type TailBuildLogResponse struct {
+	// The complete lines of the build log starting at the requested offset.
+	Lines string `protobuf:"bytes,1,opt,name=lines,proto3" json:"lines,omitempty"`
+	// The offset to request next to continue reading the build log.
+	NextOffset int64 `protobuf:"varint,2,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"`
+	// Whether the build has finished, so no more lines will be added to the log.
+	Complete             bool     `protobuf:"varint,3,opt,name=complete,proto3" json:"complete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TailBuildLogResponse) Reset()         { *m = TailBuildLogResponse{} }
+func (m *TailBuildLogResponse) String() string { return proto.CompactTextString(m) }
+func (*TailBuildLogResponse) ProtoMessage()    {}
+func (*TailBuildLogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{15}
+}
+
+func (m *TailBuildLogResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TailBuildLogResponse.Unmarshal(m, b)
+}
+func (m *TailBuildLogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TailBuildLogResponse.Marshal(b, m, deterministic)
+}
+func (m *TailBuildLogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailBuildLogResponse.Merge(m, src)
+}
+func (m *TailBuildLogResponse) XXX_Size() int {
+	return xxx_messageInfo_TailBuildLogResponse.Size(m)
+}
+func (m *TailBuildLogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailBuildLogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TailBuildLogResponse proto.InternalMessageInfo
+
+func (m *TailBuildLogResponse) GetLines() string {
+	if m != nil {
+		return m.Lines
+	}
+	return ""
+}
+
+func (m *TailBuildLogResponse) GetNextOffset() int64 {
+	if m != nil {
+		return m.NextOffset
+	}
+	return 0
+}
+
+func (m *TailBuildLogResponse) GetComplete() bool {
+	if m != nil {
+		return m.Complete
+	}
+	return false
+}
+
+type DownloadRecordRequest struct {
+	// The ID of the record to download.
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The ID of the build for the record.
+	BuildId int64 `protobuf:"varint,2,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	// The file name of the record.
+	FileName             string   `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DownloadRecordRequest) Reset()         { *m = DownloadRecordRequest{} }
+func (m *DownloadRecordRequest) String() string { return proto.CompactTextString(m) }
+func (*DownloadRecordRequest) ProtoMessage()    {}
+func (*DownloadRecordRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{16}
+}
+
+func (m *DownloadRecordRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DownloadRecordRequest.Unmarshal(m, b)
+}
+func (m *DownloadRecordRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DownloadRecordRequest.Marshal(b, m, deterministic)
+}
+func (m *DownloadRecordRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DownloadRecordRequest.Merge(m, src)
+}
+func (m *DownloadRecordRequest) XXX_Size() int {
+	return xxx_messageInfo_DownloadRecordRequest.Size(m)
+}
+func (m *DownloadRecordRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DownloadRecordRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DownloadRecordRequest proto.InternalMessageInfo
+
+func (m *DownloadRecordRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *DownloadRecordRequest) GetBuildId() int64 {
+	if m != nil {
+		return m.BuildId
+	}
+	return 0
+}
+
+func (m *DownloadRecordRequest) GetFileName() string {
+	if m != nil {
+		return m.FileName
+	}
+	return ""
+}
+
+type DownloadRecordResponse struct {
+	// The contents of the record.
+	Contents             []byte   `protobuf:"bytes,1,opt,name=contents,proto3" json:"contents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DownloadRecordResponse) Reset()         { *m = DownloadRecordResponse{} }
+func (m *DownloadRecordResponse) String() string { return proto.CompactTextString(m) }
+func (*DownloadRecordResponse) ProtoMessage()    {}
+func (*DownloadRecordResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{17}
+}
+
+func (m *DownloadRecordResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DownloadRecordResponse.Unmarshal(m, b)
+}
+func (m *DownloadRecordResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DownloadRecordResponse.Marshal(b, m, deterministic)
+}
+func (m *DownloadRecordResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DownloadRecordResponse.Merge(m, src)
+}
+func (m *DownloadRecordResponse) XXX_Size() int {
+	return xxx_messageInfo_DownloadRecordResponse.Size(m)
+}
+func (m *DownloadRecordResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DownloadRecordResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DownloadRecordResponse proto.InternalMessageInfo
+
+func (m *DownloadRecordResponse) GetContents() []byte {
+	if m != nil {
+		return m.Contents
+	}
+	return nil
+}
+
+type GetRecordURLRequest struct {
+	// The ID of the record to get a download URL for.
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The ID of the build for the record.
+	BuildId int64 `protobuf:"varint,2,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	// The file name of the record.
+	FileName             string   `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRecordURLRequest) Reset()         { *m = GetRecordURLRequest{} }
+func (m *GetRecordURLRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRecordURLRequest) ProtoMessage()    {}
+func (*GetRecordURLRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{18}
+}
+
+func (m *GetRecordURLRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRecordURLRequest.Unmarshal(m, b)
+}
+func (m *GetRecordURLRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRecordURLRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRecordURLRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRecordURLRequest.Merge(m, src)
+}
+func (m *GetRecordURLRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRecordURLRequest.Size(m)
+}
+func (m *GetRecordURLRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRecordURLRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRecordURLRequest proto.InternalMessageInfo
+
+func (m *GetRecordURLRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *GetRecordURLRequest) GetBuildId() int64 {
+	if m != nil {
+		return m.BuildId
+	}
+	return 0
+}
+
+func (m *GetRecordURLRequest) GetFileName() string {
+	if m != nil {
+		return m.FileName
+	}
+	return ""
+}
+
+type GetRecordURLResponse struct {
+	// The public S3 URL for the build record.
+	Url                  string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRecordURLResponse) Reset()         { *m = GetRecordURLResponse{} }
+func (m *GetRecordURLResponse) String() string { return proto.CompactTextString(m) }
+func (*GetRecordURLResponse) ProtoMessage()    {}
+func (*GetRecordURLResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{19}
+}
+
+func (m *GetRecordURLResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRecordURLResponse.Unmarshal(m, b)
+}
+func (m *GetRecordURLResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRecordURLResponse.Marshal(b, m, deterministic)
+}
+func (m *GetRecordURLResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRecordURLResponse.Merge(m, src)
+}
+func (m *GetRecordURLResponse) XXX_Size() int {
+	return xxx_messageInfo_GetRecordURLResponse.Size(m)
+}
+func (m *GetRecordURLResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRecordURLResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRecordURLResponse proto.InternalMessageInfo
+
+func (m *GetRecordURLResponse) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+type AttachRecordRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FileName             string   `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	Contents             []byte   `protobuf:"bytes,3,opt,name=contents,proto3" json:"contents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AttachRecordRequest) Reset()         { *m = AttachRecordRequest{} }
+func (m *AttachRecordRequest) String() string { return proto.CompactTextString(m) }
+func (*AttachRecordRequest) ProtoMessage()    {}
+func (*AttachRecordRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{20}
+}
+
+func (m *AttachRecordRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AttachRecordRequest.Unmarshal(m, b)
+}
+func (m *AttachRecordRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AttachRecordRequest.Marshal(b, m, deterministic)
+}
+func (m *AttachRecordRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttachRecordRequest.Merge(m, src)
+}
+func (m *AttachRecordRequest) XXX_Size() int {
+	return xxx_messageInfo_AttachRecordRequest.Size(m)
+}
+func (m *AttachRecordRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttachRecordRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttachRecordRequest proto.InternalMessageInfo
+
+func (m *AttachRecordRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *AttachRecordRequest) GetFileName() string {
+	if m != nil {
+		return m.FileName
+	}
+	return ""
+}
+
+func (m *AttachRecordRequest) GetContents() []byte {
+	if m != nil {
+		return m.Contents
+	}
+	return nil
+}
+
+type AttachRecordResponse struct {
+	// The record that was created.
+	Record               *Record  `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AttachRecordResponse) Reset()         { *m = AttachRecordResponse{} }
+func (m *AttachRecordResponse) String() string { return proto.CompactTextString(m) }
+func (*AttachRecordResponse) ProtoMessage()    {}
+func (*AttachRecordResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{21}
+}
+
+func (m *AttachRecordResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AttachRecordResponse.Unmarshal(m, b)
+}
+func (m *AttachRecordResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AttachRecordResponse.Marshal(b, m, deterministic)
+}
+func (m *AttachRecordResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttachRecordResponse.Merge(m, src)
+}
+func (m *AttachRecordResponse) XXX_Size() int {
+	return xxx_messageInfo_AttachRecordResponse.Size(m)
+}
+func (m *AttachRecordResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttachRecordResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttachRecordResponse proto.InternalMessageInfo
+
+func (m *AttachRecordResponse) GetRecord() *Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+type CreateScheduleRequest struct {
+	// The name of the Packer template that should be built.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The Git revision of the Packer templates repo that should be built.
+	Revision string `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	// When to build the template, as a five-field cron expression in UTC, like "0 4 * * 1".
+	CronExpression       string   `protobuf:"bytes,3,opt,name=cron_expression,json=cronExpression,proto3" json:"cron_expression,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateScheduleRequest) Reset()         { *m = CreateScheduleRequest{} }
+func (m *CreateScheduleRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateScheduleRequest) ProtoMessage()    {}
+func (*CreateScheduleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{22}
+}
+
+func (m *CreateScheduleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateScheduleRequest.Unmarshal(m, b)
+}
+func (m *CreateScheduleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateScheduleRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateScheduleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateScheduleRequest.Merge(m, src)
+}
+func (m *CreateScheduleRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateScheduleRequest.Size(m)
+}
+func (m *CreateScheduleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateScheduleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateScheduleRequest proto.InternalMessageInfo
+
+func (m *CreateScheduleRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateScheduleRequest) GetRevision() string {
+	if m != nil {
+		return m.Revision
+	}
+	return ""
+}
+
+func (m *CreateScheduleRequest) GetCronExpression() string {
+	if m != nil {
+		return m.CronExpression
+	}
+	return ""
+}
+
+type CreateScheduleResponse struct {
+	Schedule             *Schedule `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CreateScheduleResponse) Reset()         { *m = CreateScheduleResponse{} }
+func (m *CreateScheduleResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateScheduleResponse) ProtoMessage()    {}
+func (*CreateScheduleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{23}
+}
+
+func (m *CreateScheduleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateScheduleResponse.Unmarshal(m, b)
+}
+func (m *CreateScheduleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateScheduleResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateScheduleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateScheduleResponse.Merge(m, src)
+}
+func (m *CreateScheduleResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateScheduleResponse.Size(m)
+}
+func (m *CreateScheduleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateScheduleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateScheduleResponse proto.InternalMessageInfo
+
+func (m *CreateScheduleResponse) GetSchedule() *Schedule {
+	if m != nil {
+		return m.Schedule
+	}
+	return nil
+}
+
+type ListSchedulesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSchedulesRequest) Reset()         { *m = ListSchedulesRequest{} }
+func (m *ListSchedulesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSchedulesRequest) ProtoMessage()    {}
+func (*ListSchedulesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{24}
+}
+
+func (m *ListSchedulesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSchedulesRequest.Unmarshal(m, b)
+}
+func (m *ListSchedulesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSchedulesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSchedulesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSchedulesRequest.Merge(m, src)
+}
+func (m *ListSchedulesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSchedulesRequest.Size(m)
+}
+func (m *ListSchedulesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSchedulesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSchedulesRequest proto.InternalMessageInfo
+
+type ListSchedulesResponse struct {
+	Schedules            []*Schedule `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListSchedulesResponse) Reset()         { *m = ListSchedulesResponse{} }
+func (m *ListSchedulesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSchedulesResponse) ProtoMessage()    {}
+func (*ListSchedulesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{25}
+}
+
+func (m *ListSchedulesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSchedulesResponse.Unmarshal(m, b)
+}
+func (m *ListSchedulesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSchedulesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSchedulesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSchedulesResponse.Merge(m, src)
+}
+func (m *ListSchedulesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSchedulesResponse.Size(m)
+}
+func (m *ListSchedulesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSchedulesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSchedulesResponse proto.InternalMessageInfo
+
+func (m *ListSchedulesResponse) GetSchedules() []*Schedule {
+	if m != nil {
+		return m.Schedules
+	}
+	return nil
+}
+
+type DeleteScheduleRequest struct {
+	// The ID of the schedule to delete.
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteScheduleRequest) Reset()         { *m = DeleteScheduleRequest{} }
+func (m *DeleteScheduleRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteScheduleRequest) ProtoMessage()    {}
+func (*DeleteScheduleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{26}
+}
+
+func (m *DeleteScheduleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteScheduleRequest.Unmarshal(m, b)
+}
+func (m *DeleteScheduleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteScheduleRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteScheduleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteScheduleRequest.Merge(m, src)
+}
+func (m *DeleteScheduleRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteScheduleRequest.Size(m)
+}
+func (m *DeleteScheduleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteScheduleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteScheduleRequest proto.InternalMessageInfo
+
+func (m *DeleteScheduleRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type DeleteScheduleResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteScheduleResponse) Reset()         { *m = DeleteScheduleResponse{} }
+func (m *DeleteScheduleResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteScheduleResponse) ProtoMessage()    {}
+func (*DeleteScheduleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{27}
+}
+
+func (m *DeleteScheduleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteScheduleResponse.Unmarshal(m, b)
+}
+func (m *DeleteScheduleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteScheduleResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteScheduleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteScheduleResponse.Merge(m, src)
+}
+func (m *DeleteScheduleResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteScheduleResponse.Size(m)
+}
+func (m *DeleteScheduleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteScheduleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteScheduleResponse proto.InternalMessageInfo
+
+type Schedule struct {
+	Id             int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Revision       string `protobuf:"bytes,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	CronExpression string `protobuf:"bytes,4,opt,name=cron_expression,json=cronExpression,proto3" json:"cron_expression,omitempty"`
+	// The next time the schedule will start a build.
+	NextRunAt int64 `protobuf:"varint,5,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"`
+	// The last time the schedule ran, or zero if it hasn't run yet.
+	LastRunAt int64 `protobuf:"varint,6,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"`
+	// The ID of the last build the schedule started, or zero if it hasn't started one.
+	LastBuildId          int64    `protobuf:"varint,7,opt,name=last_build_id,json=lastBuildId,proto3" json:"last_build_id,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Schedule) Reset()         { *m = Schedule{} }
+func (m *Schedule) String() string { return proto.CompactTextString(m) }
+func (*Schedule) ProtoMessage()    {}
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{28}
+}
+
+func (m *Schedule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Schedule.Unmarshal(m, b)
+}
+func (m *Schedule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Schedule.Marshal(b, m, deterministic)
+}
+func (m *Schedule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Schedule.Merge(m, src)
+}
+func (m *Schedule) XXX_Size() int {
+	return xxx_messageInfo_Schedule.Size(m)
+}
+func (m *Schedule) XXX_DiscardUnknown() {
+	xxx_messageInfo_Schedule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Schedule proto.InternalMessageInfo
+
+func (m *Schedule) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Schedule) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Schedule) GetRevision() string {
+	if m != nil {
+		return m.Revision
+	}
+	return ""
+}
+
+func (m *Schedule) GetCronExpression() string {
+	if m != nil {
+		return m.CronExpression
+	}
+	return ""
+}
+
+func (m *Schedule) GetNextRunAt() int64 {
+	if m != nil {
+		return m.NextRunAt
+	}
+	return 0
+}
+
+func (m *Schedule) GetLastRunAt() int64 {
+	if m != nil {
+		return m.LastRunAt
+	}
+	return 0
+}
+
+func (m *Schedule) GetLastBuildId() int64 {
+	if m != nil {
+		return m.LastBuildId
+	}
+	return 0
+}
+
+func (m *Schedule) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type ListAuditEventsRequest struct {
+	// The most events to return. Defaults to 20, and can be at most 100.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The next_page_token from a previous response, to get the next page of events.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Only list calls made by this principal.
+	Principal string `protobuf:"bytes,3,opt,name=principal,proto3" json:"principal,omitempty"`
+	// Only list calls to this method, like "StartBuild".
+	Method string `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	// Only list calls with this result, like "ok" or "invalid_argument".
+	Code string `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
+	// Only list calls made at or after this Unix time.
+	CreatedAfter int64 `protobuf:"varint,6,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	// Only list calls made before this Unix time.
+	CreatedBefore        int64    `protobuf:"varint,7,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAuditEventsRequest) Reset()         { *m = ListAuditEventsRequest{} }
+func (m *ListAuditEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListAuditEventsRequest) ProtoMessage()    {}
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{29}
+}
+
+func (m *ListAuditEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAuditEventsRequest.Unmarshal(m, b)
+}
+func (m *ListAuditEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAuditEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListAuditEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAuditEventsRequest.Merge(m, src)
+}
+func (m *ListAuditEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListAuditEventsRequest.Size(m)
+}
+func (m *ListAuditEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAuditEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAuditEventsRequest proto.InternalMessageInfo
+
+func (m *ListAuditEventsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListAuditEventsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListAuditEventsRequest) GetPrincipal() string {
+	if m != nil {
+		return m.Principal
+	}
+	return ""
+}
+
+func (m *ListAuditEventsRequest) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *ListAuditEventsRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *ListAuditEventsRequest) GetCreatedAfter() int64 {
+	if m != nil {
+		return m.CreatedAfter
+	}
+	return 0
+}
+
+func (m *ListAuditEventsRequest) GetCreatedBefore() int64 {
+	if m != nil {
+		return m.CreatedBefore
+	}
+	return 0
+}
+
+type ListAuditEventsResponse struct {
+	// The matching events, newest first.
+	Events []*AuditEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// The token to request the next page of events, or empty if this is the last page.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAuditEventsResponse) Reset()         { *m = ListAuditEventsResponse{} }
+func (m *ListAuditEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAuditEventsResponse) ProtoMessage()    {}
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{30}
+}
+
+func (m *ListAuditEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAuditEventsResponse.Unmarshal(m, b)
+}
+func (m *ListAuditEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAuditEventsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListAuditEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAuditEventsResponse.Merge(m, src)
+}
+func (m *ListAuditEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAuditEventsResponse.Size(m)
+}
+func (m *ListAuditEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAuditEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAuditEventsResponse proto.InternalMessageInfo
+
+func (m *ListAuditEventsResponse) GetEvents() []*AuditEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *ListAuditEventsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// AuditEvent is a call to an API method that changes something.
+type AuditEvent struct {
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Who made the call: the name of the API token, or what made it automatically.
+	Principal string `protobuf:"bytes,2,opt,name=principal,proto3" json:"principal,omitempty"`
+	Method    string `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	// The main arguments of the call.
+	Summary string `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"`
+	// The Twirp error code of the call, or "ok" if it succeeded.
+	Code                 string   `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEvent) Reset()         { *m = AuditEvent{} }
+func (m *AuditEvent) String() string { return proto.CompactTextString(m) }
+func (*AuditEvent) ProtoMessage()    {}
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{31}
+}
+
+func (m *AuditEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEvent.Unmarshal(m, b)
+}
+func (m *AuditEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEvent.Marshal(b, m, deterministic)
+}
+func (m *AuditEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEvent.Merge(m, src)
+}
+func (m *AuditEvent) XXX_Size() int {
+	return xxx_messageInfo_AuditEvent.Size(m)
+}
+func (m *AuditEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEvent proto.InternalMessageInfo
+
+func (m *AuditEvent) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *AuditEvent) GetPrincipal() string {
+	if m != nil {
+		return m.Principal
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetSummary() string {
+	if m != nil {
+		return m.Summary
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type ListTemplatesRequest struct {
+	// The Git revision of the Packer templates repo to read the templates from.
+	//
+	// Defaults to master.
+	Revision             string   `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTemplatesRequest) Reset()         { *m = ListTemplatesRequest{} }
+func (m *ListTemplatesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTemplatesRequest) ProtoMessage()    {}
+func (*ListTemplatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{32}
+}
+
+func (m *ListTemplatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTemplatesRequest.Unmarshal(m, b)
+}
+func (m *ListTemplatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTemplatesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTemplatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTemplatesRequest.Merge(m, src)
+}
+func (m *ListTemplatesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTemplatesRequest.Size(m)
+}
+func (m *ListTemplatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTemplatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTemplatesRequest proto.InternalMessageInfo
+
+func (m *ListTemplatesRequest) GetRevision() string {
+	if m != nil {
+		return m.Revision
+	}
+	return ""
+}
+
+type ListTemplatesResponse struct {
+	Templates []*Template `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	// The commit SHA that the requested revision resolved to.
+	FullRevision string `protobuf:"bytes,2,opt,name=full_revision,json=fullRevision,proto3" json:"full_revision,omitempty"`
+	// The templates that could not be parsed, which are left out of templates.
+	InvalidTemplates     []*InvalidTemplate `protobuf:"bytes,3,rep,name=invalid_templates,json=invalidTemplates,proto3" json:"invalid_templates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ListTemplatesResponse) Reset()         { *m = ListTemplatesResponse{} }
+func (m *ListTemplatesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListTemplatesResponse) ProtoMessage()    {}
+func (*ListTemplatesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{33}
+}
+
+func (m *ListTemplatesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTemplatesResponse.Unmarshal(m, b)
+}
+func (m *ListTemplatesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTemplatesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListTemplatesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTemplatesResponse.Merge(m, src)
+}
+func (m *ListTemplatesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListTemplatesResponse.Size(m)
+}
+func (m *ListTemplatesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTemplatesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTemplatesResponse proto.InternalMessageInfo
+
+func (m *ListTemplatesResponse) GetTemplates() []*Template {
+	if m != nil {
+		return m.Templates
+	}
+	return nil
+}
+
+func (m *ListTemplatesResponse) GetFullRevision() string {
+	if m != nil {
+		return m.FullRevision
+	}
+	return ""
+}
+
+func (m *ListTemplatesResponse) GetInvalidTemplates() []*InvalidTemplate {
+	if m != nil {
+		return m.InvalidTemplates
+	}
+	return nil
+}
+
+type InvalidTemplate struct {
+	// The name of the Packer template.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Why the template could not be parsed.
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InvalidTemplate) Reset()         { *m = InvalidTemplate{} }
+func (m *InvalidTemplate) String() string { return proto.CompactTextString(m) }
+func (*InvalidTemplate) ProtoMessage()    {}
+func (*InvalidTemplate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{34}
+}
+
+func (m *InvalidTemplate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InvalidTemplate.Unmarshal(m, b)
+}
+func (m *InvalidTemplate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InvalidTemplate.Marshal(b, m, deterministic)
+}
+func (m *InvalidTemplate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InvalidTemplate.Merge(m, src)
+}
+func (m *InvalidTemplate) XXX_Size() int {
+	return xxx_messageInfo_InvalidTemplate.Size(m)
+}
+func (m *InvalidTemplate) XXX_DiscardUnknown() {
+	xxx_messageInfo_InvalidTemplate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InvalidTemplate proto.InternalMessageInfo
+
+func (m *InvalidTemplate) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InvalidTemplate) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type GetTemplateRequest struct {
+	// The name of the Packer template.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The Git revision of the Packer templates repo to read the template from.
+	//
+	// Defaults to master.
+	Revision             string   `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTemplateRequest) Reset()         { *m = GetTemplateRequest{} }
+func (m *GetTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTemplateRequest) ProtoMessage()    {}
+func (*GetTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{35}
+}
+
+func (m *GetTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTemplateRequest.Unmarshal(m, b)
+}
+func (m *GetTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTemplateRequest.Merge(m, src)
+}
+func (m *GetTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTemplateRequest.Size(m)
+}
+func (m *GetTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTemplateRequest proto.InternalMessageInfo
+
+func (m *GetTemplateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetTemplateRequest) GetRevision() string {
+	if m != nil {
+		return m.Revision
+	}
+	return ""
+}
+
+type GetTemplateResponse struct {
+	Template *Template `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	// The commit SHA that the requested revision resolved to.
+	FullRevision         string   `protobuf:"bytes,2,opt,name=full_revision,json=fullRevision,proto3" json:"full_revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTemplateResponse) Reset()         { *m = GetTemplateResponse{} }
+func (m *GetTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*GetTemplateResponse) ProtoMessage()    {}
+func (*GetTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{36}
+}
+
+func (m *GetTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTemplateResponse.Unmarshal(m, b)
+}
+func (m *GetTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTemplateResponse.Marshal(b, m, deterministic)
+}
+func (m *GetTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTemplateResponse.Merge(m, src)
+}
+func (m *GetTemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_GetTemplateResponse.Size(m)
+}
+func (m *GetTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTemplateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTemplateResponse proto.InternalMessageInfo
+
+func (m *GetTemplateResponse) GetTemplate() *Template {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+func (m *GetTemplateResponse) GetFullRevision() string {
+	if m != nil {
+		return m.FullRevision
+	}
+	return ""
+}
+
+type Template struct {
+	// The name to use when starting a build of the template.
+	Name     string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Builders []*Template_Builder `protobuf:"bytes,2,rep,name=builders,proto3" json:"builders,omitempty"`
+	// The user variables the template accepts, with their default values.
+	//
+	// Variables that must be set for a build have an empty default.
+	Variables    map[string]string       `protobuf:"bytes,3,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Provisioners []*Template_Provisioner `protobuf:"bytes,4,rep,name=provisioners,proto3" json:"provisioners,omitempty"`
+	// The most recent build of the template, if it has been built before.
+	LastBuild            *Build   `protobuf:"bytes,5,opt,name=last_build,json=lastBuild,proto3" json:"last_build,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Template) Reset()         { *m = Template{} }
+func (m *Template) String() string { return proto.CompactTextString(m) }
+func (*Template) ProtoMessage()    {}
+func (*Template) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{37}
+}
+
+func (m *Template) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Template.Unmarshal(m, b)
+}
+func (m *Template) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Template.Marshal(b, m, deterministic)
+}
+func (m *Template) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Template.Merge(m, src)
+}
+func (m *Template) XXX_Size() int {
+	return xxx_messageInfo_Template.Size(m)
+}
+func (m *Template) XXX_DiscardUnknown() {
+	xxx_messageInfo_Template.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Template proto.InternalMessageInfo
+
+func (m *Template) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Template) GetBuilders() []*Template_Builder {
+	if m != nil {
+		return m.Builders
+	}
+	return nil
+}
+
+func (m *Template) GetVariables() map[string]string {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+func (m *Template) GetProvisioners() []*Template_Provisioner {
+	if m != nil {
+		return m.Provisioners
+	}
+	return nil
+}
+
+func (m *Template) GetLastBuild() *Build {
+	if m != nil {
+		return m.LastBuild
+	}
+	return nil
+}
+
+type Template_Builder struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Template_Builder) Reset()         { *m = Template_Builder{} }
+func (m *Template_Builder) String() string { return proto.CompactTextString(m) }
+func (*Template_Builder) ProtoMessage()    {}
+func (*Template_Builder) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{37, 0}
+}
+
+func (m *Template_Builder) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Template_Builder.Unmarshal(m, b)
+}
+func (m *Template_Builder) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Template_Builder.Marshal(b, m, deterministic)
+}
+func (m *Template_Builder) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Template_Builder.Merge(m, src)
+}
+func (m *Template_Builder) XXX_Size() int {
+	return xxx_messageInfo_Template_Builder.Size(m)
+}
+func (m *Template_Builder) XXX_DiscardUnknown() {
+	xxx_messageInfo_Template_Builder.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Template_Builder proto.InternalMessageInfo
+
+func (m *Template_Builder) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Template_Builder) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+type Template_Provisioner struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Template_Provisioner) Reset()         { *m = Template_Provisioner{} }
+func (m *Template_Provisioner) String() string { return proto.CompactTextString(m) }
+func (*Template_Provisioner) ProtoMessage()    {}
+func (*Template_Provisioner) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{37, 1}
+}
+
+func (m *Template_Provisioner) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Template_Provisioner.Unmarshal(m, b)
+}
+func (m *Template_Provisioner) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Template_Provisioner.Marshal(b, m, deterministic)
+}
+func (m *Template_Provisioner) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Template_Provisioner.Merge(m, src)
+}
+func (m *Template_Provisioner) XXX_Size() int {
+	return xxx_messageInfo_Template_Provisioner.Size(m)
+}
+func (m *Template_Provisioner) XXX_DiscardUnknown() {
+	xxx_messageInfo_Template_Provisioner.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Template_Provisioner proto.InternalMessageInfo
+
+func (m *Template_Provisioner) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+type Build struct {
+	Id            int64             `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string            `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Revision      string            `protobuf:"bytes,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	FullRevision  string            `protobuf:"bytes,4,opt,name=full_revision,json=fullRevision,proto3" json:"full_revision,omitempty"`
+	Status        Build_Status      `protobuf:"varint,5,opt,name=status,proto3,enum=travisci.images.Build_Status" json:"status,omitempty"`
+	CreatedAt     int64             `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt     int64             `protobuf:"varint,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    int64             `protobuf:"varint,8,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Records       []*Record         `protobuf:"bytes,9,rep,name=records,proto3" json:"records,omitempty"`
+	FailureReason string            `protobuf:"bytes,10,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	Variables     map[string]string `protobuf:"bytes,11,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	VarFiles      []string          `protobuf:"bytes,12,rep,name=var_files,json=varFiles,proto3" json:"var_files,omitempty"`
+	Artifacts     []*Artifact       `protobuf:"bytes,13,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
+	// The timeout requested for the build, or zero if it uses the template's timeout.
+	TimeoutSeconds int64 `protobuf:"varint,14,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	// Each run of the build, in order. A failed build may be retried automatically.
+	Attempts []*Attempt `protobuf:"bytes,15,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	// The ID of the build that this build is a retry of, if any.
+	RetriedFrom int64 `protobuf:"varint,16,opt,name=retried_from,json=retriedFrom,proto3" json:"retried_from,omitempty"`
+	// Who started the build: the name of the API token, or what started it automatically.
+	Principal string `protobuf:"bytes,17,opt,name=principal,proto3" json:"principal,omitempty"`
+	// Whether the build's image was released. Records of released builds are never deleted.
+	Released             bool     `protobuf:"varint,18,opt,name=released,proto3" json:"released,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Build) Reset()         { *m = Build{} }
 func (m *Build) String() string { return proto.CompactTextString(m) }
 func (*Build) ProtoMessage()    {}
 func (*Build) Descriptor() ([]byte, []int) {
-	return fileDescriptor_03e4ae55c7c0e319, []int{14}
+	return fileDescriptor_03e4ae55c7c0e319, []int{38}
 }
 
 func (m *Build) XXX_Unmarshal(b []byte) error {
@@ -760,11 +2238,265 @@ func (m *Build) GetRecords() []*Record {
 	return nil
 }
 
+func (m *Build) GetFailureReason() string {
+	if m != nil {
+		return m.FailureReason
+	}
+	return ""
+}
+
+func (m *Build) GetVariables() map[string]string {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+func (m *Build) GetVarFiles() []string {
+	if m != nil {
+		return m.VarFiles
+	}
+	return nil
+}
+
+func (m *Build) GetArtifacts() []*Artifact {
+	if m != nil {
+		return m.Artifacts
+	}
+	return nil
+}
+
+func (m *Build) GetTimeoutSeconds() int64 {
+	if m != nil {
+		return m.TimeoutSeconds
+	}
+	return 0
+}
+
+func (m *Build) GetAttempts() []*Attempt {
+	if m != nil {
+		return m.Attempts
+	}
+	return nil
+}
+
+func (m *Build) GetRetriedFrom() int64 {
+	if m != nil {
+		return m.RetriedFrom
+	}
+	return 0
+}
+
+func (m *Build) GetPrincipal() string {
+	if m != nil {
+		return m.Principal
+	}
+	return ""
+}
+
+func (m *Build) GetReleased() bool {
+	if m != nil {
+		return m.Released
+	}
+	return false
+}
+
+type Attempt struct {
+	Id      int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BuildId int64 `protobuf:"varint,2,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	// The number of the attempt, starting from 1.
+	Number               int32        `protobuf:"varint,3,opt,name=number,proto3" json:"number,omitempty"`
+	Status               Build_Status `protobuf:"varint,4,opt,name=status,proto3,enum=travisci.images.Build_Status" json:"status,omitempty"`
+	StartedAt            int64        `protobuf:"varint,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt           int64        `protobuf:"varint,6,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	FailureReason        string       `protobuf:"bytes,7,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Attempt) Reset()         { *m = Attempt{} }
+func (m *Attempt) String() string { return proto.CompactTextString(m) }
+func (*Attempt) ProtoMessage()    {}
+func (*Attempt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{39}
+}
+
+func (m *Attempt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Attempt.Unmarshal(m, b)
+}
+func (m *Attempt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Attempt.Marshal(b, m, deterministic)
+}
+func (m *Attempt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Attempt.Merge(m, src)
+}
+func (m *Attempt) XXX_Size() int {
+	return xxx_messageInfo_Attempt.Size(m)
+}
+func (m *Attempt) XXX_DiscardUnknown() {
+	xxx_messageInfo_Attempt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Attempt proto.InternalMessageInfo
+
+func (m *Attempt) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Attempt) GetBuildId() int64 {
+	if m != nil {
+		return m.BuildId
+	}
+	return 0
+}
+
+func (m *Attempt) GetNumber() int32 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *Attempt) GetStatus() Build_Status {
+	if m != nil {
+		return m.Status
+	}
+	return Build_CREATED
+}
+
+func (m *Attempt) GetStartedAt() int64 {
+	if m != nil {
+		return m.StartedAt
+	}
+	return 0
+}
+
+func (m *Attempt) GetFinishedAt() int64 {
+	if m != nil {
+		return m.FinishedAt
+	}
+	return 0
+}
+
+func (m *Attempt) GetFailureReason() string {
+	if m != nil {
+		return m.FailureReason
+	}
+	return ""
+}
+
+type Artifact struct {
+	Id      int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BuildId int64 `protobuf:"varint,2,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	// The name of the Packer builder that produced the artifact.
+	Builder string `protobuf:"bytes,3,opt,name=builder,proto3" json:"builder,omitempty"`
+	// The ID of the type of Packer builder, like "jetbrains.vsphere".
+	BuilderId string `protobuf:"bytes,4,opt,name=builder_id,json=builderId,proto3" json:"builder_id,omitempty"`
+	// The builder-specific ID of the artifact, like the name of a VM.
+	ArtifactId string `protobuf:"bytes,5,opt,name=artifact_id,json=artifactId,proto3" json:"artifact_id,omitempty"`
+	// A human-readable description of the artifact.
+	Description string `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	// Local files that make up the artifact, if any.
+	Files                []string `protobuf:"bytes,7,rep,name=files,proto3" json:"files,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Artifact) Reset()         { *m = Artifact{} }
+func (m *Artifact) String() string { return proto.CompactTextString(m) }
+func (*Artifact) ProtoMessage()    {}
+func (*Artifact) Descriptor() ([]byte, []int) {
+	return fileDescriptor_03e4ae55c7c0e319, []int{40}
+}
+
+func (m *Artifact) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Artifact.Unmarshal(m, b)
+}
+func (m *Artifact) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Artifact.Marshal(b, m, deterministic)
+}
+func (m *Artifact) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Artifact.Merge(m, src)
+}
+func (m *Artifact) XXX_Size() int {
+	return xxx_messageInfo_Artifact.Size(m)
+}
+func (m *Artifact) XXX_DiscardUnknown() {
+	xxx_messageInfo_Artifact.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Artifact proto.InternalMessageInfo
+
+func (m *Artifact) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Artifact) GetBuildId() int64 {
+	if m != nil {
+		return m.BuildId
+	}
+	return 0
+}
+
+func (m *Artifact) GetBuilder() string {
+	if m != nil {
+		return m.Builder
+	}
+	return ""
+}
+
+func (m *Artifact) GetBuilderId() string {
+	if m != nil {
+		return m.BuilderId
+	}
+	return ""
+}
+
+func (m *Artifact) GetArtifactId() string {
+	if m != nil {
+		return m.ArtifactId
+	}
+	return ""
+}
+
+func (m *Artifact) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Artifact) GetFiles() []string {
+	if m != nil {
+		return m.Files
+	}
+	return nil
+}
+
 type Record struct {
-	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	BuildId              int64    `protobuf:"varint,2,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
-	FileName             string   `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
-	S3Key                string   `protobuf:"bytes,4,opt,name=s3_key,json=s3Key,proto3" json:"s3_key,omitempty"`
+	Id       int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BuildId  int64  `protobuf:"varint,2,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	FileName string `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	S3Key    string `protobuf:"bytes,4,opt,name=s3_key,json=s3Key,proto3" json:"s3_key,omitempty"`
+	// The ID of the attempt that produced the record, or zero if it was attached later.
+	AttemptId int64 `protobuf:"varint,5,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`
+	// The hex-encoded SHA-256 checksum of the record's contents, for verifying downloads.
+	//
+	// This and the other metadata are empty for records saved by older versions of imaged.
+	Sha256 string `protobuf:"bytes,6,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// The size of the record in bytes.
+	Size int64 `protobuf:"varint,7,opt,name=size,proto3" json:"size,omitempty"`
+	// The MIME type of the record, detected when it was uploaded.
+	ContentType          string   `protobuf:"bytes,8,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -774,7 +2506,7 @@ func (m *Record) Reset()         { *m = Record{} }
 func (m *Record) String() string { return proto.CompactTextString(m) }
 func (*Record) ProtoMessage()    {}
 func (*Record) Descriptor() ([]byte, []int) {
-	return fileDescriptor_03e4ae55c7c0e319, []int{15}
+	return fileDescriptor_03e4ae55c7c0e319, []int{41}
 }
 
 func (m *Record) XXX_Unmarshal(b []byte) error {
@@ -823,6 +2555,41 @@ func (m *Record) GetS3Key() string {
 	return ""
 }
 
+func (m *Record) GetAttemptId() int64 {
+	if m != nil {
+		return m.AttemptId
+	}
+	return 0
+}
+
+func (m *Record) GetSha256() string {
+	if m != nil {
+		return m.Sha256
+	}
+	return ""
+}
+
+func (m *Record) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *Record) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *Record) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("travisci.images.Build_Status", Build_Status_name, Build_Status_value)
 	proto.RegisterType((*ListBuildsRequest)(nil), "travisci.images.ListBuildsRequest")
@@ -832,63 +2599,178 @@ func init() {
 	proto.RegisterType((*GetLastBuildRequest)(nil), "travisci.images.GetLastBuildRequest")
 	proto.RegisterType((*GetLastBuildResponse)(nil), "travisci.images.GetLastBuildResponse")
 	proto.RegisterType((*StartBuildRequest)(nil), "travisci.images.StartBuildRequest")
+	proto.RegisterMapType((map[string]string)(nil), "travisci.images.StartBuildRequest.VariablesEntry")
 	proto.RegisterType((*StartBuildResponse)(nil), "travisci.images.StartBuildResponse")
+	proto.RegisterType((*CancelBuildRequest)(nil), "travisci.images.CancelBuildRequest")
+	proto.RegisterType((*CancelBuildResponse)(nil), "travisci.images.CancelBuildResponse")
+	proto.RegisterType((*RetryBuildRequest)(nil), "travisci.images.RetryBuildRequest")
+	proto.RegisterType((*RetryBuildResponse)(nil), "travisci.images.RetryBuildResponse")
+	proto.RegisterType((*SetBuildReleasedRequest)(nil), "travisci.images.SetBuildReleasedRequest")
+	proto.RegisterType((*SetBuildReleasedResponse)(nil), "travisci.images.SetBuildReleasedResponse")
+	proto.RegisterType((*TailBuildLogRequest)(nil), "travisci.images.TailBuildLogRequest")
+	proto.RegisterType((*TailBuildLogResponse)(nil), "travisci.images.TailBuildLogResponse")
 	proto.RegisterType((*DownloadRecordRequest)(nil), "travisci.images.DownloadRecordRequest")
 	proto.RegisterType((*DownloadRecordResponse)(nil), "travisci.images.DownloadRecordResponse")
 	proto.RegisterType((*GetRecordURLRequest)(nil), "travisci.images.GetRecordURLRequest")
 	proto.RegisterType((*GetRecordURLResponse)(nil), "travisci.images.GetRecordURLResponse")
 	proto.RegisterType((*AttachRecordRequest)(nil), "travisci.images.AttachRecordRequest")
 	proto.RegisterType((*AttachRecordResponse)(nil), "travisci.images.AttachRecordResponse")
+	proto.RegisterType((*CreateScheduleRequest)(nil), "travisci.images.CreateScheduleRequest")
+	proto.RegisterType((*CreateScheduleResponse)(nil), "travisci.images.CreateScheduleResponse")
+	proto.RegisterType((*ListSchedulesRequest)(nil), "travisci.images.ListSchedulesRequest")
+	proto.RegisterType((*ListSchedulesResponse)(nil), "travisci.images.ListSchedulesResponse")
+	proto.RegisterType((*DeleteScheduleRequest)(nil), "travisci.images.DeleteScheduleRequest")
+	proto.RegisterType((*DeleteScheduleResponse)(nil), "travisci.images.DeleteScheduleResponse")
+	proto.RegisterType((*Schedule)(nil), "travisci.images.Schedule")
+	proto.RegisterType((*ListAuditEventsRequest)(nil), "travisci.images.ListAuditEventsRequest")
+	proto.RegisterType((*ListAuditEventsResponse)(nil), "travisci.images.ListAuditEventsResponse")
+	proto.RegisterType((*AuditEvent)(nil), "travisci.images.AuditEvent")
+	proto.RegisterType((*ListTemplatesRequest)(nil), "travisci.images.ListTemplatesRequest")
+	proto.RegisterType((*ListTemplatesResponse)(nil), "travisci.images.ListTemplatesResponse")
+	proto.RegisterType((*InvalidTemplate)(nil), "travisci.images.InvalidTemplate")
+	proto.RegisterType((*GetTemplateRequest)(nil), "travisci.images.GetTemplateRequest")
+	proto.RegisterType((*GetTemplateResponse)(nil), "travisci.images.GetTemplateResponse")
+	proto.RegisterType((*Template)(nil), "travisci.images.Template")
+	proto.RegisterMapType((map[string]string)(nil), "travisci.images.Template.VariablesEntry")
+	proto.RegisterType((*Template_Builder)(nil), "travisci.images.Template.Builder")
+	proto.RegisterType((*Template_Provisioner)(nil), "travisci.images.Template.Provisioner")
 	proto.RegisterType((*Build)(nil), "travisci.images.Build")
+	proto.RegisterMapType((map[string]string)(nil), "travisci.images.Build.VariablesEntry")
+	proto.RegisterType((*Attempt)(nil), "travisci.images.Attempt")
+	proto.RegisterType((*Artifact)(nil), "travisci.images.Artifact")
 	proto.RegisterType((*Record)(nil), "travisci.images.Record")
 }
 
 func init() { proto.RegisterFile("rpc/images/service.proto", fileDescriptor_03e4ae55c7c0e319) }
 
 var fileDescriptor_03e4ae55c7c0e319 = []byte{
-	// 696 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5f, 0x4f, 0xdb, 0x3e,
-	0x14, 0xfd, 0x35, 0xa1, 0x69, 0x7b, 0x29, 0x50, 0xcc, 0x9f, 0x5f, 0xd6, 0x09, 0xad, 0x98, 0xfd,
-	0xe9, 0xa4, 0xa9, 0x68, 0xb0, 0x3d, 0x4e, 0x5a, 0x68, 0x3a, 0x84, 0x56, 0x6d, 0x52, 0x4a, 0x5f,
-	0x86, 0xb6, 0x2a, 0x24, 0x06, 0xac, 0x95, 0x84, 0xc5, 0x2e, 0x13, 0x8f, 0xfb, 0xce, 0xfb, 0x00,
-	0x53, 0x6c, 0xb7, 0x34, 0x8d, 0x0b, 0x12, 0xe2, 0x2d, 0xbe, 0xf7, 0xdc, 0x73, 0xaf, 0x8f, 0xaf,
-	0x8e, 0x02, 0x76, 0x72, 0x15, 0xec, 0xd2, 0x4b, 0xff, 0x9c, 0xb0, 0x5d, 0x46, 0x92, 0x6b, 0x1a,
-	0x90, 0xd6, 0x55, 0x12, 0xf3, 0x18, 0xad, 0xf0, 0xc4, 0xbf, 0xa6, 0x2c, 0xa0, 0x2d, 0x99, 0xc6,
-	0x6b, 0xb0, 0xda, 0xa5, 0x8c, 0x1f, 0x8c, 0xe8, 0x30, 0x64, 0x1e, 0xf9, 0x35, 0x22, 0x8c, 0x63,
-	0x17, 0xd0, 0x74, 0x90, 0x5d, 0xc5, 0x11, 0x23, 0xa8, 0x05, 0xd6, 0xa9, 0x88, 0xd8, 0x85, 0x86,
-	0xd9, 0x5c, 0xdc, 0xdb, 0x6c, 0xcd, 0x90, 0xb5, 0x44, 0x81, 0xa7, 0x50, 0x78, 0x1b, 0x56, 0x0e,
-	0x89, 0x24, 0x51, 0xc4, 0x68, 0x19, 0x0c, 0x1a, 0xda, 0x85, 0x46, 0xa1, 0x69, 0x7a, 0x06, 0x0d,
-	0xf1, 0x47, 0xa8, 0xdd, 0x42, 0x54, 0x9b, 0x37, 0x50, 0x14, 0x04, 0x02, 0x36, 0xbf, 0x8b, 0x04,
-	0xe1, 0xd7, 0xb0, 0x76, 0x48, 0x78, 0xd7, 0x67, 0xd9, 0x46, 0x08, 0x16, 0x22, 0xff, 0x92, 0x08,
-	0x8e, 0x8a, 0x27, 0xbe, 0xb1, 0x0b, 0xeb, 0x59, 0xe8, 0x83, 0x1a, 0xb6, 0x61, 0xb5, 0xc7, 0xfd,
-	0xe4, 0xde, 0x76, 0xa8, 0x0e, 0xe5, 0x84, 0x5c, 0x53, 0x46, 0xe3, 0xc8, 0x36, 0x44, 0x7c, 0x72,
-	0xc6, 0x07, 0x80, 0xa6, 0x49, 0x1e, 0x34, 0xc8, 0x00, 0x36, 0xdc, 0xf8, 0x77, 0x34, 0x8c, 0xfd,
-	0xd0, 0x23, 0x41, 0x9c, 0xcc, 0x13, 0x19, 0x3d, 0x81, 0xb2, 0xa8, 0x18, 0xd0, 0x50, 0x0c, 0x62,
-	0x7a, 0x25, 0x71, 0x3e, 0x0a, 0xd1, 0x53, 0xa8, 0x9c, 0xd1, 0x21, 0x19, 0x88, 0xe1, 0x4d, 0x39,
-	0x64, 0x1a, 0xf8, 0x92, 0xea, 0xf5, 0x0e, 0x36, 0x67, 0x1b, 0xa8, 0x41, 0xeb, 0x50, 0x0e, 0xe2,
-	0x88, 0x93, 0x88, 0x33, 0xd1, 0xa7, 0xea, 0x4d, 0xce, 0xf8, 0xbb, 0x78, 0x10, 0x59, 0xd0, 0xf7,
-	0xba, 0x8f, 0x3d, 0x54, 0x53, 0x3c, 0xe2, 0x14, 0xbd, 0x1a, 0xa9, 0x06, 0xe6, 0x28, 0x19, 0xaa,
-	0x07, 0x48, 0x3f, 0xf1, 0x0f, 0x58, 0x73, 0x38, 0xf7, 0x83, 0x8b, 0xbb, 0xd5, 0xc9, 0x74, 0x33,
-	0xb2, 0xdd, 0x32, 0x17, 0x35, 0x67, 0x2e, 0x7a, 0x08, 0xeb, 0x59, 0x7e, 0x35, 0xc9, 0x2e, 0x58,
-	0x89, 0x88, 0xa8, 0x67, 0xfc, 0x3f, 0xf7, 0x8c, 0xaa, 0x40, 0xc1, 0xf0, 0x5f, 0x03, 0x8a, 0xe2,
-	0x65, 0x73, 0xb3, 0x8d, 0xd7, 0xca, 0x98, 0xb3, 0x56, 0x66, 0x76, 0xad, 0xd0, 0x0e, 0x2c, 0x9d,
-	0x8d, 0x86, 0xc3, 0xc1, 0x04, 0xb0, 0x20, 0x00, 0xd5, 0x34, 0xe8, 0x8d, 0x41, 0xef, 0xc1, 0x62,
-	0xdc, 0xe7, 0x23, 0x66, 0x17, 0x1b, 0x85, 0xe6, 0xf2, 0xde, 0x96, 0x7e, 0xcd, 0x5a, 0x3d, 0x01,
-	0xf2, 0x14, 0x18, 0x6d, 0x01, 0x04, 0x09, 0xf1, 0x39, 0x09, 0x07, 0x3e, 0xb7, 0x2d, 0x31, 0x63,
-	0x45, 0x45, 0x1c, 0x9e, 0xa6, 0x59, 0xba, 0xd1, 0x32, 0x5d, 0x92, 0x69, 0x15, 0x71, 0x38, 0x7a,
-	0x06, 0x8b, 0x67, 0x34, 0xa2, 0xec, 0x42, 0xe6, 0xcb, 0x22, 0x0f, 0xe3, 0x90, 0xc3, 0xd1, 0x5b,
-	0x28, 0x49, 0x39, 0x98, 0x5d, 0x11, 0xee, 0x32, 0x57, 0xb6, 0x31, 0x0e, 0x7f, 0x00, 0x4b, 0xce,
-	0x88, 0x16, 0xa1, 0xd4, 0xf6, 0x3a, 0xce, 0x71, 0xc7, 0xad, 0xfd, 0x97, 0x1e, 0x7a, 0xc7, 0x8e,
-	0x97, 0x1e, 0x0a, 0x68, 0x09, 0x2a, 0xbd, 0x7e, 0xbb, 0xdd, 0xe9, 0xb8, 0x1d, 0xb7, 0x66, 0x20,
-	0x00, 0xeb, 0x93, 0x73, 0xd4, 0xed, 0xb8, 0x35, 0x13, 0x9f, 0x83, 0x25, 0x19, 0x1f, 0x6b, 0x37,
-	0xd1, 0x06, 0x58, 0x6c, 0x7f, 0xf0, 0x93, 0xdc, 0x28, 0xdd, 0x8b, 0x6c, 0xff, 0x33, 0xb9, 0xd9,
-	0xfb, 0x53, 0x04, 0xeb, 0x48, 0x5c, 0x01, 0xf5, 0x01, 0x6e, 0x8d, 0x15, 0xe1, 0xdc, 0x15, 0x73,
-	0x56, 0x5c, 0xdf, 0xb9, 0x13, 0xa3, 0x56, 0xee, 0x2b, 0x94, 0xc7, 0x36, 0x8a, 0x1a, 0xb9, 0x82,
-	0x19, 0x13, 0xae, 0x6f, 0xdf, 0x81, 0x50, 0x84, 0x27, 0x50, 0x9d, 0xb6, 0x4a, 0xf4, 0x5c, 0x57,
-	0x32, 0x6b, 0xba, 0xf5, 0x17, 0xf7, 0xa0, 0x14, 0x79, 0x1f, 0xe0, 0xd6, 0xfc, 0x34, 0x22, 0xe4,
-	0xec, 0x55, 0x23, 0x82, 0xc6, 0x3d, 0x7d, 0x58, 0xce, 0xda, 0x15, 0x7a, 0x99, 0x2b, 0xd3, 0x1a,
-	0x66, 0xfd, 0xd5, 0xbd, 0xb8, 0x8c, 0x2c, 0x13, 0xf3, 0xd1, 0xcb, 0x32, 0x6b, 0x7d, 0x7a, 0x59,
-	0xf2, 0x0e, 0x76, 0x02, 0xd5, 0x69, 0x3f, 0xd1, 0x90, 0x6b, 0xec, 0x4c, 0x43, 0xae, 0x33, 0xa5,
-	0x83, 0xf2, 0x37, 0x4b, 0xa6, 0x4f, 0x2d, 0xf1, 0x23, 0xb0, 0xff, 0x2f, 0x00, 0x00, 0xff, 0xff,
-	0x54, 0x90, 0x0c, 0x6a, 0x24, 0x08, 0x00, 0x00,
+	// 2040 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0x5b, 0x6f, 0xdb, 0xc8,
+	0x15, 0xae, 0x24, 0x8b, 0x12, 0x8f, 0x6f, 0xf2, 0xf8, 0x12, 0x2e, 0xd3, 0xb4, 0x36, 0x1d, 0xc7,
+	0x5e, 0xa0, 0x70, 0x10, 0x67, 0xd3, 0xed, 0x15, 0xa8, 0x6c, 0x29, 0x59, 0xa3, 0xde, 0x75, 0x4a,
+	0xdb, 0x45, 0xd1, 0xa2, 0x2b, 0xd0, 0xe2, 0xc8, 0x26, 0x96, 0x22, 0xb5, 0x33, 0x23, 0x75, 0x9d,
+	0x5f, 0xd2, 0xb7, 0xfe, 0x90, 0x02, 0x7d, 0x2a, 0xd0, 0xff, 0xd0, 0x9f, 0xd0, 0xe7, 0xa2, 0x2f,
+	0x7d, 0x59, 0xcc, 0x85, 0x77, 0x4a, 0x72, 0x9c, 0xbc, 0x69, 0xce, 0x7c, 0x33, 0xe7, 0xc2, 0x73,
+	0xce, 0x9c, 0x73, 0x04, 0x06, 0x19, 0xf5, 0x9f, 0x7b, 0x43, 0xe7, 0x06, 0xd3, 0xe7, 0x14, 0x93,
+	0x89, 0xd7, 0xc7, 0x87, 0x23, 0x12, 0xb2, 0x10, 0xad, 0x32, 0xe2, 0x4c, 0x3c, 0xda, 0xf7, 0x0e,
+	0xe5, 0xb6, 0xf5, 0xff, 0x2a, 0xac, 0x9d, 0x79, 0x94, 0x1d, 0x8f, 0x3d, 0xdf, 0xa5, 0x36, 0xfe,
+	0x76, 0x8c, 0x29, 0x43, 0x8f, 0x41, 0x1f, 0x39, 0x37, 0xb8, 0x47, 0xbd, 0x77, 0xd8, 0xa8, 0x6c,
+	0x57, 0x0e, 0xea, 0x76, 0x93, 0x13, 0x2e, 0xbc, 0x77, 0x18, 0x3d, 0x01, 0x10, 0x9b, 0x2c, 0xfc,
+	0x06, 0x07, 0x46, 0x75, 0xbb, 0x72, 0xa0, 0xdb, 0x02, 0x7e, 0xc9, 0x09, 0x08, 0xc1, 0x42, 0xe0,
+	0x0c, 0xb1, 0x51, 0x13, 0x1b, 0xe2, 0x37, 0xfa, 0x39, 0x34, 0x29, 0x73, 0xd8, 0x98, 0x62, 0x6a,
+	0x2c, 0x6c, 0xd7, 0x0e, 0x56, 0x8e, 0x9e, 0x1c, 0xe6, 0x24, 0x39, 0x14, 0x12, 0x1c, 0x5e, 0x08,
+	0x98, 0x1d, 0xc3, 0x91, 0x09, 0x4d, 0x82, 0x27, 0x1e, 0xf5, 0xc2, 0xc0, 0xa8, 0x8b, 0x2b, 0xe3,
+	0x35, 0xda, 0x85, 0xe5, 0xc1, 0xd8, 0xf7, 0x7b, 0x31, 0x40, 0x13, 0x80, 0x25, 0x4e, 0xb4, 0x53,
+	0xa0, 0x3e, 0xc1, 0x0e, 0xc3, 0x6e, 0xcf, 0x19, 0x30, 0x4c, 0x8c, 0xc6, 0x76, 0xe5, 0xa0, 0x66,
+	0x2f, 0x29, 0x62, 0x9b, 0xd3, 0xd0, 0x1e, 0xac, 0x44, 0xa0, 0x6b, 0x3c, 0x08, 0x09, 0x36, 0x9a,
+	0x02, 0x15, 0x1d, 0x3d, 0x16, 0x44, 0x0e, 0x1b, 0x78, 0x81, 0x47, 0x6f, 0xe3, 0xcb, 0x74, 0x09,
+	0x8b, 0xa8, 0xf2, 0xb6, 0x7d, 0x58, 0x8d, 0x61, 0xea, 0x3a, 0x10, 0xb8, 0xf8, 0xb4, 0xbc, 0xcf,
+	0xf2, 0x01, 0xa5, 0x8d, 0x4f, 0x47, 0x61, 0x40, 0x31, 0x3a, 0x04, 0xed, 0x5a, 0x50, 0x8c, 0xca,
+	0x76, 0xed, 0x60, 0xf1, 0x68, 0xab, 0xdc, 0x56, 0xb6, 0x42, 0xa1, 0x67, 0xb0, 0x1a, 0xe0, 0xef,
+	0x58, 0xaf, 0xf0, 0x55, 0x96, 0x39, 0xf9, 0x6d, 0xf4, 0x65, 0xac, 0x1d, 0x58, 0x7d, 0x83, 0x25,
+	0xb3, 0xe8, 0x43, 0xaf, 0x40, 0xd5, 0x73, 0xc5, 0x17, 0xae, 0xd9, 0x55, 0xcf, 0xb5, 0x7e, 0x03,
+	0xad, 0x04, 0xa2, 0xc4, 0xf9, 0x09, 0xd4, 0x05, 0x23, 0x01, 0x9b, 0x2e, 0x8d, 0x04, 0x59, 0x9f,
+	0xc2, 0xfa, 0x1b, 0xcc, 0xce, 0x1c, 0x9a, 0x65, 0x14, 0x79, 0x45, 0x25, 0xf1, 0x0a, 0xab, 0x03,
+	0x1b, 0x59, 0xe8, 0x83, 0x18, 0xfe, 0xb5, 0x0a, 0x6b, 0x17, 0xcc, 0x21, 0x73, 0xf9, 0x65, 0x5c,
+	0xa9, 0x9a, 0x73, 0xa5, 0x73, 0xd0, 0x27, 0x0e, 0xf1, 0x9c, 0x6b, 0x1f, 0x53, 0xa3, 0x26, 0xcc,
+	0xfe, 0xa2, 0xc0, 0xb7, 0xc0, 0xe6, 0xf0, 0xf7, 0xd1, 0x99, 0x6e, 0xc0, 0xc8, 0x9d, 0x9d, 0xdc,
+	0xc1, 0x43, 0x68, 0xe2, 0x90, 0xde, 0xc0, 0xf3, 0x95, 0xcf, 0xeb, 0x76, 0x73, 0xe2, 0x90, 0xd7,
+	0x7c, 0xcd, 0x1d, 0x84, 0x79, 0x43, 0x1c, 0x8e, 0x59, 0x8f, 0xe2, 0x7e, 0x18, 0xb8, 0x54, 0xf8,
+	0x76, 0xcd, 0x5e, 0x51, 0xe4, 0x0b, 0x49, 0x35, 0x7f, 0x05, 0x2b, 0x59, 0x16, 0xa8, 0x05, 0xb5,
+	0x6f, 0xf0, 0x9d, 0xd2, 0x8b, 0xff, 0x44, 0x1b, 0x50, 0x9f, 0x38, 0xfe, 0x18, 0x2b, 0x9d, 0xe4,
+	0xe2, 0x17, 0xd5, 0x9f, 0x55, 0xac, 0x63, 0x40, 0x69, 0x91, 0x1f, 0x64, 0xde, 0xa7, 0x80, 0x4e,
+	0x9c, 0xa0, 0x8f, 0xfd, 0x99, 0x7e, 0x73, 0x02, 0xeb, 0x19, 0xd4, 0x83, 0x58, 0xed, 0xc2, 0x9a,
+	0x8d, 0x19, 0xb9, 0x9b, 0xc9, 0xe9, 0x18, 0x50, 0x1a, 0xf4, 0x20, 0x46, 0x5d, 0x78, 0x74, 0x11,
+	0x7b, 0xb9, 0x8f, 0x1d, 0x8a, 0xa7, 0xb1, 0x93, 0x3e, 0x23, 0x21, 0xc2, 0xbe, 0x4d, 0x3b, 0x5e,
+	0x5b, 0x5f, 0x80, 0x51, 0xbc, 0xe6, 0x41, 0x02, 0x7d, 0x01, 0xeb, 0x97, 0x8e, 0x27, 0x8d, 0x77,
+	0x16, 0xde, 0x44, 0xc2, 0x7c, 0x02, 0x4d, 0xb1, 0xdf, 0x8b, 0x45, 0x6a, 0x88, 0xf5, 0xa9, 0x8b,
+	0xb6, 0x40, 0x0b, 0x07, 0x03, 0x8a, 0x99, 0x90, 0xaa, 0x66, 0xab, 0x95, 0xe5, 0xc1, 0x46, 0xf6,
+	0x26, 0x25, 0xcf, 0x06, 0xd4, 0x7d, 0x2f, 0xc0, 0x54, 0x39, 0x8e, 0x5c, 0xa0, 0x1f, 0xc3, 0xa2,
+	0xc8, 0x1c, 0x99, 0xab, 0x80, 0x93, 0xce, 0x05, 0x85, 0xab, 0xdf, 0x0f, 0x87, 0x23, 0x1f, 0x33,
+	0x99, 0xd0, 0x9b, 0x76, 0xbc, 0xb6, 0x7a, 0xb0, 0xd9, 0x09, 0xff, 0x12, 0xf8, 0xa1, 0xe3, 0xda,
+	0xb8, 0x1f, 0x92, 0xa9, 0x36, 0x4c, 0xab, 0x51, 0xcd, 0xaa, 0xf1, 0x18, 0x74, 0x1e, 0x21, 0xbd,
+	0xd4, 0x8b, 0xd1, 0xe4, 0x84, 0xaf, 0x78, 0x7e, 0xf8, 0x0c, 0xb6, 0xf2, 0x0c, 0x94, 0x36, 0x42,
+	0xac, 0x80, 0xe1, 0x80, 0x49, 0x85, 0x96, 0xec, 0x78, 0x6d, 0xfd, 0x59, 0x24, 0x20, 0x79, 0xe0,
+	0xca, 0x3e, 0xfb, 0xd8, 0x42, 0x1d, 0xc0, 0x46, 0xf6, 0x7a, 0x25, 0x52, 0x0b, 0x6a, 0x63, 0xe2,
+	0x47, 0x71, 0x39, 0x26, 0xbe, 0xf5, 0x35, 0xac, 0xb7, 0x19, 0x73, 0xfa, 0xb7, 0xb3, 0xad, 0x93,
+	0xe1, 0x56, 0xcd, 0x72, 0xcb, 0x28, 0x5a, 0xcb, 0x29, 0xfa, 0x06, 0x36, 0xb2, 0xf7, 0x2b, 0x49,
+	0x9e, 0x83, 0x46, 0x04, 0x45, 0xf9, 0xde, 0xa3, 0x82, 0xef, 0xa9, 0x03, 0x0a, 0x66, 0x8d, 0x60,
+	0xf3, 0x44, 0x3c, 0x73, 0x17, 0xfd, 0x5b, 0xec, 0x8e, 0x7d, 0xfc, 0xd0, 0x24, 0xba, 0x0f, 0xab,
+	0x7d, 0x12, 0x06, 0x3d, 0xfc, 0xdd, 0x88, 0x60, 0x2a, 0x20, 0xd2, 0x7c, 0x2b, 0x9c, 0xdc, 0x8d,
+	0xa9, 0xd6, 0x39, 0x6c, 0xe5, 0x39, 0x2a, 0xe1, 0x5f, 0x41, 0x93, 0x2a, 0x9a, 0x12, 0xff, 0x93,
+	0x62, 0x1a, 0x8e, 0x0e, 0xc5, 0x50, 0x6b, 0x0b, 0x36, 0xf8, 0x43, 0x1a, 0xed, 0x44, 0x85, 0x8c,
+	0xf5, 0x16, 0x36, 0x73, 0x74, 0xc5, 0xe7, 0x73, 0xd0, 0xa3, 0xc3, 0xd1, 0x33, 0x3b, 0x83, 0x51,
+	0x82, 0xb5, 0xf6, 0x61, 0xb3, 0x83, 0x7d, 0x5c, 0x34, 0x56, 0x3e, 0x51, 0x19, 0xb0, 0x95, 0x07,
+	0x4a, 0xde, 0xd6, 0xff, 0x2a, 0xd0, 0x8c, 0x88, 0x05, 0x77, 0x88, 0x6c, 0x5e, 0x9d, 0x62, 0xf3,
+	0xda, 0x7c, 0x9b, 0x2f, 0x94, 0xd9, 0x1c, 0xfd, 0x48, 0xc5, 0x3a, 0x19, 0x07, 0x3d, 0x87, 0xa9,
+	0xf7, 0x46, 0xe7, 0x24, 0x7b, 0x1c, 0xb4, 0x19, 0xdf, 0xf7, 0x1d, 0x1a, 0xef, 0x6b, 0x72, 0x9f,
+	0x93, 0xe4, 0xbe, 0x05, 0xcb, 0x62, 0x3f, 0x8e, 0x1a, 0x59, 0x47, 0x89, 0x43, 0xc7, 0x2a, 0x72,
+	0x9e, 0x00, 0xc4, 0xb5, 0x16, 0x53, 0x25, 0x94, 0x1e, 0x15, 0x5a, 0xcc, 0xfa, 0x4f, 0x05, 0xb6,
+	0xf8, 0xe7, 0x68, 0x8f, 0x5d, 0x8f, 0x75, 0x27, 0xdc, 0x8b, 0x3f, 0x46, 0xc5, 0xf9, 0x43, 0xd0,
+	0x47, 0xc4, 0x0b, 0xfa, 0xde, 0xc8, 0xf1, 0x95, 0x7d, 0x12, 0x02, 0xcf, 0x94, 0x43, 0xcc, 0x6e,
+	0x43, 0x57, 0xd9, 0x45, 0xad, 0xb8, 0xa1, 0xfb, 0xa1, 0x8b, 0x55, 0x51, 0x29, 0x7e, 0x17, 0x6b,
+	0x45, 0xed, 0x5e, 0xb5, 0x62, 0xa3, 0xa4, 0x56, 0xb4, 0x26, 0xf0, 0xa8, 0xa0, 0xab, 0x72, 0xbe,
+	0x97, 0xa0, 0xe1, 0x89, 0x4a, 0x5e, 0xdc, 0xf3, 0x1e, 0x17, 0x3c, 0x2f, 0x39, 0x65, 0x2b, 0xe8,
+	0xbd, 0xab, 0xbc, 0xbf, 0x55, 0x00, 0x92, 0xe3, 0x05, 0xff, 0xca, 0x18, 0xab, 0x3a, 0xdd, 0x58,
+	0xb5, 0x8c, 0xb1, 0x0c, 0x68, 0xd0, 0xf1, 0x70, 0xe8, 0x90, 0x3b, 0x65, 0xc5, 0x68, 0x59, 0x6a,
+	0xc6, 0xac, 0x1b, 0x68, 0x79, 0x37, 0x38, 0x92, 0xc1, 0x7a, 0x89, 0x87, 0x23, 0xdf, 0x61, 0x71,
+	0xb0, 0x66, 0xdc, 0xbc, 0x92, 0x75, 0x73, 0xeb, 0x9f, 0x15, 0xd8, 0xcc, 0x1d, 0x4a, 0x22, 0x99,
+	0x45, 0xc4, 0xa9, 0x91, 0x1c, 0x1d, 0xb3, 0x13, 0x6c, 0xb1, 0x7b, 0xa8, 0x96, 0x74, 0x0f, 0x5f,
+	0xc2, 0x9a, 0x17, 0x4c, 0x1c, 0xdf, 0x73, 0x7b, 0x09, 0x17, 0x59, 0x1f, 0x6e, 0x17, 0xb8, 0x9c,
+	0x4a, 0x64, 0xcc, 0xac, 0xe5, 0x65, 0x09, 0xd4, 0xfa, 0x25, 0xac, 0xe6, 0x40, 0xa5, 0x49, 0x76,
+	0x03, 0xea, 0x98, 0x90, 0x90, 0x44, 0x25, 0x9d, 0x58, 0x58, 0x1d, 0x40, 0x6f, 0x70, 0x6c, 0x81,
+	0x07, 0x26, 0x69, 0xeb, 0x5b, 0x58, 0xcf, 0xdc, 0x92, 0x24, 0xde, 0x48, 0xc1, 0xa9, 0x89, 0x37,
+	0x3e, 0x14, 0x43, 0xef, 0x65, 0x44, 0xeb, 0xef, 0x35, 0x68, 0xce, 0xd4, 0xf7, 0xd7, 0xea, 0x31,
+	0xc6, 0x84, 0x1a, 0x55, 0x61, 0xdc, 0x9d, 0xa9, 0xcc, 0x65, 0xe5, 0x84, 0x89, 0x1d, 0x1f, 0x41,
+	0xaf, 0x8b, 0xc5, 0xfb, 0xc1, 0xf4, 0xf3, 0xd3, 0x6b, 0xf6, 0x53, 0x58, 0x1a, 0x91, 0x50, 0x0a,
+	0x8d, 0x89, 0x2c, 0xdb, 0x17, 0x8f, 0xf6, 0xa6, 0x5f, 0xf5, 0x36, 0x41, 0xdb, 0x99, 0xa3, 0xe8,
+	0x15, 0x40, 0x92, 0x2d, 0x8d, 0xfa, 0xcc, 0x22, 0x50, 0x8f, 0x53, 0xa8, 0xf9, 0x02, 0x1a, 0x4a,
+	0xbd, 0x52, 0x3b, 0x21, 0x58, 0x60, 0x77, 0xa3, 0xf8, 0x71, 0xe0, 0xbf, 0xcd, 0x1d, 0x58, 0x4c,
+	0x89, 0x11, 0x43, 0x2a, 0x29, 0xc8, 0x87, 0x75, 0x11, 0xff, 0xd6, 0xa0, 0x2e, 0x84, 0xfa, 0xe0,
+	0xb7, 0xaa, 0xe0, 0x2c, 0x0b, 0x25, 0x11, 0xf7, 0x0a, 0x34, 0xd9, 0xfc, 0x0b, 0xab, 0xcd, 0x9d,
+	0x14, 0x28, 0xf0, 0x9c, 0x9c, 0xc3, 0xb7, 0x29, 0x73, 0x88, 0xda, 0x96, 0x09, 0x5b, 0x57, 0x94,
+	0x36, 0xe3, 0x85, 0x70, 0xd2, 0xd8, 0x47, 0x2f, 0x17, 0xc4, 0x5d, 0x3d, 0x43, 0x2f, 0xa0, 0x21,
+	0xab, 0x25, 0x6a, 0xe8, 0xdb, 0xb5, 0x59, 0x55, 0x55, 0x84, 0x13, 0xc3, 0x02, 0xc7, 0xf3, 0xc7,
+	0x04, 0xf7, 0x08, 0x76, 0x68, 0x18, 0x88, 0x21, 0x80, 0x6e, 0x2f, 0x2b, 0xaa, 0x2d, 0x88, 0xe8,
+	0x24, 0xed, 0xbc, 0x8b, 0x53, 0x3c, 0x4e, 0xaa, 0x7c, 0xcf, 0x6e, 0x73, 0x29, 0xd7, 0x6d, 0x7e,
+	0x0e, 0xba, 0x43, 0x98, 0x37, 0x70, 0xfa, 0x8c, 0x1a, 0xcb, 0x53, 0x32, 0x64, 0x5b, 0x21, 0xec,
+	0x04, 0x5b, 0xd6, 0xa6, 0xae, 0x94, 0xb5, 0xa9, 0xe8, 0x33, 0x68, 0x3a, 0x8c, 0xe7, 0x04, 0x46,
+	0x8d, 0x55, 0xc1, 0xc0, 0x28, 0x32, 0x90, 0x00, 0x3b, 0x46, 0xa2, 0x1d, 0x58, 0x22, 0x98, 0x11,
+	0x0f, 0xbb, 0xbd, 0x01, 0x09, 0x87, 0x46, 0x4b, 0x16, 0x14, 0x8a, 0xf6, 0x9a, 0x84, 0xc3, 0xec,
+	0x6b, 0xb5, 0x96, 0x7f, 0xad, 0xd2, 0xcd, 0x19, 0xca, 0x36, 0x67, 0x1f, 0xe8, 0xf3, 0x37, 0xa0,
+	0x49, 0xff, 0x42, 0x8b, 0xd0, 0x38, 0xb1, 0xbb, 0xed, 0xcb, 0x6e, 0xa7, 0xf5, 0x03, 0xbe, 0xb8,
+	0xb8, 0x6c, 0xdb, 0x7c, 0x51, 0x41, 0xcb, 0xa0, 0x5f, 0x5c, 0x9d, 0x9c, 0x74, 0xbb, 0x9d, 0x6e,
+	0xa7, 0x55, 0x45, 0x00, 0xda, 0xeb, 0xf6, 0xe9, 0x59, 0xb7, 0xd3, 0xaa, 0xf1, 0xdf, 0xbf, 0xbb,
+	0xea, 0x5e, 0x75, 0x3b, 0xad, 0x05, 0x0e, 0x3b, 0x69, 0x7f, 0x75, 0xd2, 0x3d, 0xe3, 0x5b, 0x75,
+	0xbe, 0xbc, 0x3c, 0xfd, 0xb2, 0xdb, 0xe9, 0x9d, 0x5f, 0x5d, 0xb6, 0x34, 0x5e, 0x12, 0x35, 0x94,
+	0x65, 0xde, 0xa7, 0x45, 0xd9, 0x02, 0x2d, 0x18, 0x0f, 0xaf, 0x31, 0x11, 0x31, 0x56, 0xb7, 0xd5,
+	0x2a, 0x15, 0x3c, 0x0b, 0xef, 0x19, 0x3c, 0xa9, 0xe8, 0xa8, 0xcf, 0x89, 0x0e, 0xad, 0x10, 0x1d,
+	0x45, 0x57, 0x6f, 0x94, 0xb8, 0xba, 0xf5, 0xaf, 0x0a, 0x34, 0x23, 0x3f, 0x7b, 0x1f, 0x6d, 0x0d,
+	0x68, 0xa8, 0x5c, 0xaf, 0x52, 0x4a, 0xb4, 0xe4, 0x82, 0xab, 0x9f, 0xfc, 0x98, 0x4c, 0x27, 0xba,
+	0xa2, 0x9c, 0xba, 0x5c, 0xf0, 0xc8, 0x9b, 0xf9, 0xbe, 0xac, 0x51, 0x20, 0x22, 0x9d, 0xba, 0x68,
+	0x1b, 0x16, 0x5d, 0x4c, 0xfb, 0xc4, 0x1b, 0xb1, 0x64, 0x7e, 0x98, 0x26, 0x71, 0x1f, 0x91, 0x51,
+	0xd5, 0x10, 0x51, 0x25, 0x17, 0xd6, 0x7f, 0x2b, 0xa0, 0xc9, 0x78, 0xff, 0x58, 0x8d, 0x25, 0xda,
+	0x04, 0x8d, 0xbe, 0xec, 0x71, 0x0f, 0x95, 0x6a, 0xd4, 0xe9, 0xcb, 0xdf, 0xe2, 0x3b, 0xae, 0xa1,
+	0x0a, 0x98, 0x48, 0x83, 0x9a, 0xad, 0x2b, 0x8a, 0x74, 0x04, 0x7a, 0xeb, 0x1c, 0xbd, 0xfa, 0xa9,
+	0x92, 0x5d, 0xad, 0x78, 0x6a, 0x16, 0xa5, 0xb4, 0xcc, 0x74, 0xe2, 0x37, 0x8f, 0x37, 0xd5, 0x3c,
+	0xf6, 0xc4, 0x13, 0xd1, 0x94, 0xda, 0x2a, 0xda, 0xe5, 0xdd, 0x28, 0x5f, 0xb9, 0xe9, 0xb9, 0x2c,
+	0x7a, 0xf4, 0x8f, 0x25, 0xd0, 0x4e, 0x85, 0x1f, 0xa1, 0x2b, 0x80, 0x64, 0x74, 0x89, 0xac, 0x82,
+	0x9f, 0x15, 0x86, 0xca, 0xe6, 0xee, 0x4c, 0x8c, 0x2a, 0x43, 0xce, 0xa1, 0x19, 0x0d, 0x20, 0x51,
+	0xb1, 0xc0, 0xca, 0x8d, 0x2f, 0xcd, 0x9d, 0x19, 0x08, 0x75, 0xe1, 0x9f, 0x60, 0x29, 0x3d, 0x64,
+	0x44, 0x4f, 0xcb, 0x8e, 0xe4, 0xc7, 0x95, 0xe6, 0xde, 0x1c, 0x94, 0xba, 0xfc, 0x0a, 0x20, 0x19,
+	0xb0, 0x95, 0x18, 0xa1, 0x30, 0x30, 0x34, 0x77, 0x67, 0x62, 0xd4, 0xb5, 0x7f, 0x80, 0xc5, 0xd4,
+	0x34, 0x0d, 0x15, 0xcf, 0x14, 0x27, 0x72, 0xe6, 0xd3, 0xd9, 0xa0, 0x44, 0xe0, 0x64, 0x7a, 0x56,
+	0x22, 0x70, 0x61, 0xfe, 0x66, 0xee, 0xce, 0xc4, 0xa8, 0x6b, 0x6f, 0xa0, 0x95, 0x9f, 0x84, 0xa1,
+	0x62, 0x05, 0x36, 0x65, 0xe6, 0x66, 0x7e, 0x7a, 0x0f, 0x64, 0xf2, 0x35, 0xd3, 0xe3, 0xad, 0x92,
+	0xaf, 0x59, 0x32, 0x47, 0x33, 0xf7, 0xe6, 0xa0, 0xd4, 0xe5, 0x0e, 0xac, 0x64, 0xa7, 0x12, 0xe8,
+	0x59, 0xd1, 0xa8, 0x65, 0x83, 0x12, 0x73, 0x7f, 0x2e, 0x4e, 0xb1, 0xf8, 0x1a, 0x96, 0x33, 0xf3,
+	0x08, 0xb4, 0x57, 0x1a, 0x14, 0xf9, 0x39, 0x86, 0xf9, 0x6c, 0x1e, 0x2c, 0x51, 0x21, 0x3b, 0x74,
+	0x28, 0x51, 0xa1, 0x74, 0x7c, 0x61, 0xee, 0xcf, 0xc5, 0x29, 0x16, 0x2e, 0xac, 0xe6, 0xfa, 0x5a,
+	0xb4, 0x5f, 0x2a, 0x5d, 0xb1, 0xcb, 0x37, 0x0f, 0xe6, 0x03, 0xb3, 0x86, 0x8a, 0x3b, 0xa7, 0x29,
+	0x86, 0xca, 0xf7, 0x90, 0xe6, 0xb3, 0x79, 0xb0, 0x24, 0xc4, 0x52, 0x5d, 0x50, 0x49, 0x88, 0x15,
+	0x3b, 0x2d, 0xf3, 0xe9, 0x6c, 0x50, 0xea, 0x13, 0x64, 0xa6, 0x96, 0x65, 0x9f, 0xa0, 0x6c, 0x6e,
+	0x6a, 0xee, 0xcf, 0xc5, 0x65, 0x72, 0x5a, 0x3c, 0x83, 0x2c, 0xcf, 0x69, 0xf9, 0x09, 0xa8, 0xb9,
+	0x37, 0x07, 0x95, 0x5c, 0x9e, 0x1e, 0x2b, 0x96, 0x5c, 0x5e, 0x32, 0xd5, 0x34, 0xf7, 0xe6, 0xa0,
+	0xe4, 0xe5, 0xc7, 0xcd, 0x3f, 0x6a, 0x72, 0xfb, 0x5a, 0x13, 0x7f, 0x48, 0xbe, 0xfc, 0x7e, 0x00,
+	0x63, 0xce, 0xdf, 0x48, 0xac, 0x1c, 0x00, 0x00,
 }
@@ -41,6 +41,26 @@ type Images interface {
 
 	StartBuild(context.Context, *StartBuildRequest) (*StartBuildResponse, error)
 
+	CancelBuild(context.Context, *CancelBuildRequest) (*CancelBuildResponse, error)
+
+	RetryBuild(context.Context, *RetryBuildRequest) (*RetryBuildResponse, error)
+
+	SetBuildReleased(context.Context, *SetBuildReleasedRequest) (*SetBuildReleasedResponse, error)
+
+	TailBuildLog(context.Context, *TailBuildLogRequest) (*TailBuildLogResponse, error)
+
+	CreateSchedule(context.Context, *CreateScheduleRequest) (*CreateScheduleResponse, error)
+
+	ListSchedules(context.Context, *ListSchedulesRequest) (*ListSchedulesResponse, error)
+
+	DeleteSchedule(context.Context, *DeleteScheduleRequest) (*DeleteScheduleResponse, error)
+
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+
+	ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error)
+
+	GetTemplate(context.Context, *GetTemplateRequest) (*GetTemplateResponse, error)
+
 	DownloadRecord(context.Context, *DownloadRecordRequest) (*DownloadRecordResponse, error)
 
 	GetRecordURL(context.Context, *GetRecordURLRequest) (*GetRecordURLResponse, error)
@@ -54,18 +74,28 @@ type Images interface {
 
 type imagesProtobufClient struct {
 	client HTTPClient
-	urls   [7]string
+	urls   [17]string
 }
 
 // NewImagesProtobufClient creates a Protobuf client that implements the Images interface.
 // It communicates using Protobuf and can be configured with a custom HTTPClient.
 func NewImagesProtobufClient(addr string, client HTTPClient) Images {
 	prefix := urlBase(addr) + ImagesPathPrefix
-	urls := [7]string{
+	urls := [17]string{
 		prefix + "ListBuilds",
 		prefix + "GetBuild",
 		prefix + "GetLastBuild",
 		prefix + "StartBuild",
+		prefix + "CancelBuild",
+		prefix + "RetryBuild",
+		prefix + "SetBuildReleased",
+		prefix + "TailBuildLog",
+		prefix + "CreateSchedule",
+		prefix + "ListSchedules",
+		prefix + "DeleteSchedule",
+		prefix + "ListAuditEvents",
+		prefix + "ListTemplates",
+		prefix + "GetTemplate",
 		prefix + "DownloadRecord",
 		prefix + "GetRecordURL",
 		prefix + "AttachRecord",
@@ -130,12 +160,132 @@ func (c *imagesProtobufClient) StartBuild(ctx context.Context, in *StartBuildReq
 	return out, nil
 }
 
+func (c *imagesProtobufClient) CancelBuild(ctx context.Context, in *CancelBuildRequest) (*CancelBuildResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "CancelBuild")
+	out := new(CancelBuildResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[4], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesProtobufClient) RetryBuild(ctx context.Context, in *RetryBuildRequest) (*RetryBuildResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "RetryBuild")
+	out := new(RetryBuildResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[5], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesProtobufClient) SetBuildReleased(ctx context.Context, in *SetBuildReleasedRequest) (*SetBuildReleasedResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "SetBuildReleased")
+	out := new(SetBuildReleasedResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[6], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesProtobufClient) TailBuildLog(ctx context.Context, in *TailBuildLogRequest) (*TailBuildLogResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "TailBuildLog")
+	out := new(TailBuildLogResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[7], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesProtobufClient) CreateSchedule(ctx context.Context, in *CreateScheduleRequest) (*CreateScheduleResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "CreateSchedule")
+	out := new(CreateScheduleResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[8], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesProtobufClient) ListSchedules(ctx context.Context, in *ListSchedulesRequest) (*ListSchedulesResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "ListSchedules")
+	out := new(ListSchedulesResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[9], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesProtobufClient) DeleteSchedule(ctx context.Context, in *DeleteScheduleRequest) (*DeleteScheduleResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "DeleteSchedule")
+	out := new(DeleteScheduleResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[10], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesProtobufClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "ListAuditEvents")
+	out := new(ListAuditEventsResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[11], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesProtobufClient) ListTemplates(ctx context.Context, in *ListTemplatesRequest) (*ListTemplatesResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "ListTemplates")
+	out := new(ListTemplatesResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[12], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesProtobufClient) GetTemplate(ctx context.Context, in *GetTemplateRequest) (*GetTemplateResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "GetTemplate")
+	out := new(GetTemplateResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[13], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *imagesProtobufClient) DownloadRecord(ctx context.Context, in *DownloadRecordRequest) (*DownloadRecordResponse, error) {
 	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
 	ctx = ctxsetters.WithServiceName(ctx, "Images")
 	ctx = ctxsetters.WithMethodName(ctx, "DownloadRecord")
 	out := new(DownloadRecordResponse)
-	err := doProtobufRequest(ctx, c.client, c.urls[4], in, out)
+	err := doProtobufRequest(ctx, c.client, c.urls[14], in, out)
 	if err != nil {
 		return nil, err
 	}
@@ -147,7 +297,7 @@ func (c *imagesProtobufClient) GetRecordURL(ctx context.Context, in *GetRecordUR
 	ctx = ctxsetters.WithServiceName(ctx, "Images")
 	ctx = ctxsetters.WithMethodName(ctx, "GetRecordURL")
 	out := new(GetRecordURLResponse)
-	err := doProtobufRequest(ctx, c.client, c.urls[5], in, out)
+	err := doProtobufRequest(ctx, c.client, c.urls[15], in, out)
 	if err != nil {
 		return nil, err
 	}
@@ -159,7 +309,7 @@ func (c *imagesProtobufClient) AttachRecord(ctx context.Context, in *AttachRecor
 	ctx = ctxsetters.WithServiceName(ctx, "Images")
 	ctx = ctxsetters.WithMethodName(ctx, "AttachRecord")
 	out := new(AttachRecordResponse)
-	err := doProtobufRequest(ctx, c.client, c.urls[6], in, out)
+	err := doProtobufRequest(ctx, c.client, c.urls[16], in, out)
 	if err != nil {
 		return nil, err
 	}
@@ -172,18 +322,28 @@ func (c *imagesProtobufClient) AttachRecord(ctx context.Context, in *AttachRecor
 
 type imagesJSONClient struct {
 	client HTTPClient
-	urls   [7]string
+	urls   [17]string
 }
 
 // NewImagesJSONClient creates a JSON client that implements the Images interface.
 // It communicates using JSON and can be configured with a custom HTTPClient.
 func NewImagesJSONClient(addr string, client HTTPClient) Images {
 	prefix := urlBase(addr) + ImagesPathPrefix
-	urls := [7]string{
+	urls := [17]string{
 		prefix + "ListBuilds",
 		prefix + "GetBuild",
 		prefix + "GetLastBuild",
 		prefix + "StartBuild",
+		prefix + "CancelBuild",
+		prefix + "RetryBuild",
+		prefix + "SetBuildReleased",
+		prefix + "TailBuildLog",
+		prefix + "CreateSchedule",
+		prefix + "ListSchedules",
+		prefix + "DeleteSchedule",
+		prefix + "ListAuditEvents",
+		prefix + "ListTemplates",
+		prefix + "GetTemplate",
 		prefix + "DownloadRecord",
 		prefix + "GetRecordURL",
 		prefix + "AttachRecord",
@@ -248,12 +408,132 @@ func (c *imagesJSONClient) StartBuild(ctx context.Context, in *StartBuildRequest
 	return out, nil
 }
 
+func (c *imagesJSONClient) CancelBuild(ctx context.Context, in *CancelBuildRequest) (*CancelBuildResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "CancelBuild")
+	out := new(CancelBuildResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[4], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesJSONClient) RetryBuild(ctx context.Context, in *RetryBuildRequest) (*RetryBuildResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "RetryBuild")
+	out := new(RetryBuildResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[5], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesJSONClient) SetBuildReleased(ctx context.Context, in *SetBuildReleasedRequest) (*SetBuildReleasedResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "SetBuildReleased")
+	out := new(SetBuildReleasedResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[6], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesJSONClient) TailBuildLog(ctx context.Context, in *TailBuildLogRequest) (*TailBuildLogResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "TailBuildLog")
+	out := new(TailBuildLogResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[7], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesJSONClient) CreateSchedule(ctx context.Context, in *CreateScheduleRequest) (*CreateScheduleResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "CreateSchedule")
+	out := new(CreateScheduleResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[8], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesJSONClient) ListSchedules(ctx context.Context, in *ListSchedulesRequest) (*ListSchedulesResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "ListSchedules")
+	out := new(ListSchedulesResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[9], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesJSONClient) DeleteSchedule(ctx context.Context, in *DeleteScheduleRequest) (*DeleteScheduleResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "DeleteSchedule")
+	out := new(DeleteScheduleResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[10], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesJSONClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "ListAuditEvents")
+	out := new(ListAuditEventsResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[11], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesJSONClient) ListTemplates(ctx context.Context, in *ListTemplatesRequest) (*ListTemplatesResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "ListTemplates")
+	out := new(ListTemplatesResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[12], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imagesJSONClient) GetTemplate(ctx context.Context, in *GetTemplateRequest) (*GetTemplateResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
+	ctx = ctxsetters.WithServiceName(ctx, "Images")
+	ctx = ctxsetters.WithMethodName(ctx, "GetTemplate")
+	out := new(GetTemplateResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[13], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *imagesJSONClient) DownloadRecord(ctx context.Context, in *DownloadRecordRequest) (*DownloadRecordResponse, error) {
 	ctx = ctxsetters.WithPackageName(ctx, "travisci.images")
 	ctx = ctxsetters.WithServiceName(ctx, "Images")
 	ctx = ctxsetters.WithMethodName(ctx, "DownloadRecord")
 	out := new(DownloadRecordResponse)
-	err := doJSONRequest(ctx, c.client, c.urls[4], in, out)
+	err := doJSONRequest(ctx, c.client, c.urls[14], in, out)
 	if err != nil {
 		return nil, err
 	}
@@ -265,7 +545,7 @@ func (c *imagesJSONClient) GetRecordURL(ctx context.Context, in *GetRecordURLReq
 	ctx = ctxsetters.WithServiceName(ctx, "Images")
 	ctx = ctxsetters.WithMethodName(ctx, "GetRecordURL")
 	out := new(GetRecordURLResponse)
-	err := doJSONRequest(ctx, c.client, c.urls[5], in, out)
+	err := doJSONRequest(ctx, c.client, c.urls[15], in, out)
 	if err != nil {
 		return nil, err
 	}
@@ -277,7 +557,7 @@ func (c *imagesJSONClient) AttachRecord(ctx context.Context, in *AttachRecordReq
 	ctx = ctxsetters.WithServiceName(ctx, "Images")
 	ctx = ctxsetters.WithMethodName(ctx, "AttachRecord")
 	out := new(AttachRecordResponse)
-	err := doJSONRequest(ctx, c.client, c.urls[6], in, out)
+	err := doJSONRequest(ctx, c.client, c.urls[16], in, out)
 	if err != nil {
 		return nil, err
 	}
@@ -344,6 +624,36 @@ func (s *imagesServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	case "/twirp/travisci.images.Images/StartBuild":
 		s.serveStartBuild(ctx, resp, req)
 		return
+	case "/twirp/travisci.images.Images/CancelBuild":
+		s.serveCancelBuild(ctx, resp, req)
+		return
+	case "/twirp/travisci.images.Images/RetryBuild":
+		s.serveRetryBuild(ctx, resp, req)
+		return
+	case "/twirp/travisci.images.Images/SetBuildReleased":
+		s.serveSetBuildReleased(ctx, resp, req)
+		return
+	case "/twirp/travisci.images.Images/TailBuildLog":
+		s.serveTailBuildLog(ctx, resp, req)
+		return
+	case "/twirp/travisci.images.Images/CreateSchedule":
+		s.serveCreateSchedule(ctx, resp, req)
+		return
+	case "/twirp/travisci.images.Images/ListSchedules":
+		s.serveListSchedules(ctx, resp, req)
+		return
+	case "/twirp/travisci.images.Images/DeleteSchedule":
+		s.serveDeleteSchedule(ctx, resp, req)
+		return
+	case "/twirp/travisci.images.Images/ListAuditEvents":
+		s.serveListAuditEvents(ctx, resp, req)
+		return
+	case "/twirp/travisci.images.Images/ListTemplates":
+		s.serveListTemplates(ctx, resp, req)
+		return
+	case "/twirp/travisci.images.Images/GetTemplate":
+		s.serveGetTemplate(ctx, resp, req)
+		return
 	case "/twirp/travisci.images.Images/DownloadRecord":
 		s.serveDownloadRecord(ctx, resp, req)
 		return
@@ -937,6 +1247,1446 @@ func (s *imagesServer) serveStartBuildProtobuf(ctx context.Context, resp http.Re
 	callResponseSent(ctx, s.hooks)
 }
 
+func (s *imagesServer) serveCancelBuild(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveCancelBuildJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveCancelBuildProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *imagesServer) serveCancelBuildJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "CancelBuild")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(CancelBuildRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *CancelBuildResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.CancelBuild(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *CancelBuildResponse and nil error while calling CancelBuild. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveCancelBuildProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "CancelBuild")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(CancelBuildRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *CancelBuildResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.CancelBuild(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *CancelBuildResponse and nil error while calling CancelBuild. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveRetryBuild(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveRetryBuildJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveRetryBuildProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *imagesServer) serveRetryBuildJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RetryBuild")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(RetryBuildRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RetryBuildResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.RetryBuild(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RetryBuildResponse and nil error while calling RetryBuild. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveRetryBuildProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RetryBuild")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(RetryBuildRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RetryBuildResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.RetryBuild(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RetryBuildResponse and nil error while calling RetryBuild. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveSetBuildReleased(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveSetBuildReleasedJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveSetBuildReleasedProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *imagesServer) serveSetBuildReleasedJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "SetBuildReleased")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(SetBuildReleasedRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *SetBuildReleasedResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.SetBuildReleased(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *SetBuildReleasedResponse and nil error while calling SetBuildReleased. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveSetBuildReleasedProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "SetBuildReleased")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(SetBuildReleasedRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *SetBuildReleasedResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.SetBuildReleased(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *SetBuildReleasedResponse and nil error while calling SetBuildReleased. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveTailBuildLog(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveTailBuildLogJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveTailBuildLogProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *imagesServer) serveTailBuildLogJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "TailBuildLog")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(TailBuildLogRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *TailBuildLogResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.TailBuildLog(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *TailBuildLogResponse and nil error while calling TailBuildLog. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveTailBuildLogProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "TailBuildLog")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(TailBuildLogRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *TailBuildLogResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.TailBuildLog(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *TailBuildLogResponse and nil error while calling TailBuildLog. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveCreateSchedule(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveCreateScheduleJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveCreateScheduleProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *imagesServer) serveCreateScheduleJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "CreateSchedule")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(CreateScheduleRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *CreateScheduleResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.CreateSchedule(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *CreateScheduleResponse and nil error while calling CreateSchedule. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveCreateScheduleProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "CreateSchedule")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(CreateScheduleRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *CreateScheduleResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.CreateSchedule(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *CreateScheduleResponse and nil error while calling CreateSchedule. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveListSchedules(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListSchedulesJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveListSchedulesProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *imagesServer) serveListSchedulesJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListSchedules")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(ListSchedulesRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListSchedulesResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.ListSchedules(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListSchedulesResponse and nil error while calling ListSchedules. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveListSchedulesProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListSchedules")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ListSchedulesRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListSchedulesResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.ListSchedules(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListSchedulesResponse and nil error while calling ListSchedules. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveDeleteSchedule(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveDeleteScheduleJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveDeleteScheduleProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *imagesServer) serveDeleteScheduleJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "DeleteSchedule")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(DeleteScheduleRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *DeleteScheduleResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.DeleteSchedule(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DeleteScheduleResponse and nil error while calling DeleteSchedule. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveDeleteScheduleProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "DeleteSchedule")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(DeleteScheduleRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *DeleteScheduleResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.DeleteSchedule(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DeleteScheduleResponse and nil error while calling DeleteSchedule. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveListAuditEvents(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListAuditEventsJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveListAuditEventsProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *imagesServer) serveListAuditEventsJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListAuditEvents")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(ListAuditEventsRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListAuditEventsResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.ListAuditEvents(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListAuditEventsResponse and nil error while calling ListAuditEvents. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveListAuditEventsProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListAuditEvents")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ListAuditEventsRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListAuditEventsResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.ListAuditEvents(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListAuditEventsResponse and nil error while calling ListAuditEvents. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveListTemplates(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListTemplatesJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveListTemplatesProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *imagesServer) serveListTemplatesJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListTemplates")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(ListTemplatesRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListTemplatesResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.ListTemplates(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListTemplatesResponse and nil error while calling ListTemplates. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveListTemplatesProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListTemplates")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ListTemplatesRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListTemplatesResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.ListTemplates(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListTemplatesResponse and nil error while calling ListTemplates. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveGetTemplate(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveGetTemplateJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveGetTemplateProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *imagesServer) serveGetTemplateJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "GetTemplate")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(GetTemplateRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *GetTemplateResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.GetTemplate(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *GetTemplateResponse and nil error while calling GetTemplate. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *imagesServer) serveGetTemplateProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "GetTemplate")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(GetTemplateRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *GetTemplateResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Images.GetTemplate(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *GetTemplateResponse and nil error while calling GetTemplate. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
 func (s *imagesServer) serveDownloadRecord(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	header := req.Header.Get("Content-Type")
 	i := strings.Index(header, ";")
@@ -1798,49 +3548,133 @@ func callError(ctx context.Context, h *twirp.ServerHooks, err twirp.Error) conte
 }
 
 var twirpFileDescriptor0 = []byte{
-	// 696 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5f, 0x4f, 0xdb, 0x3e,
-	0x14, 0xfd, 0x35, 0xa1, 0x69, 0x7b, 0x29, 0x50, 0xcc, 0x9f, 0x5f, 0xd6, 0x09, 0xad, 0x98, 0xfd,
-	0xe9, 0xa4, 0xa9, 0x68, 0xb0, 0x3d, 0x4e, 0x5a, 0x68, 0x3a, 0x84, 0x56, 0x6d, 0x52, 0x4a, 0x5f,
-	0x86, 0xb6, 0x2a, 0x24, 0x06, 0xac, 0x95, 0x84, 0xc5, 0x2e, 0x13, 0x8f, 0xfb, 0xce, 0xfb, 0x00,
-	0x53, 0x6c, 0xb7, 0x34, 0x8d, 0x0b, 0x12, 0xe2, 0x2d, 0xbe, 0xf7, 0xdc, 0x73, 0xaf, 0x8f, 0xaf,
-	0x8e, 0x02, 0x76, 0x72, 0x15, 0xec, 0xd2, 0x4b, 0xff, 0x9c, 0xb0, 0x5d, 0x46, 0x92, 0x6b, 0x1a,
-	0x90, 0xd6, 0x55, 0x12, 0xf3, 0x18, 0xad, 0xf0, 0xc4, 0xbf, 0xa6, 0x2c, 0xa0, 0x2d, 0x99, 0xc6,
-	0x6b, 0xb0, 0xda, 0xa5, 0x8c, 0x1f, 0x8c, 0xe8, 0x30, 0x64, 0x1e, 0xf9, 0x35, 0x22, 0x8c, 0x63,
-	0x17, 0xd0, 0x74, 0x90, 0x5d, 0xc5, 0x11, 0x23, 0xa8, 0x05, 0xd6, 0xa9, 0x88, 0xd8, 0x85, 0x86,
-	0xd9, 0x5c, 0xdc, 0xdb, 0x6c, 0xcd, 0x90, 0xb5, 0x44, 0x81, 0xa7, 0x50, 0x78, 0x1b, 0x56, 0x0e,
-	0x89, 0x24, 0x51, 0xc4, 0x68, 0x19, 0x0c, 0x1a, 0xda, 0x85, 0x46, 0xa1, 0x69, 0x7a, 0x06, 0x0d,
-	0xf1, 0x47, 0xa8, 0xdd, 0x42, 0x54, 0x9b, 0x37, 0x50, 0x14, 0x04, 0x02, 0x36, 0xbf, 0x8b, 0x04,
-	0xe1, 0xd7, 0xb0, 0x76, 0x48, 0x78, 0xd7, 0x67, 0xd9, 0x46, 0x08, 0x16, 0x22, 0xff, 0x92, 0x08,
-	0x8e, 0x8a, 0x27, 0xbe, 0xb1, 0x0b, 0xeb, 0x59, 0xe8, 0x83, 0x1a, 0xb6, 0x61, 0xb5, 0xc7, 0xfd,
-	0xe4, 0xde, 0x76, 0xa8, 0x0e, 0xe5, 0x84, 0x5c, 0x53, 0x46, 0xe3, 0xc8, 0x36, 0x44, 0x7c, 0x72,
-	0xc6, 0x07, 0x80, 0xa6, 0x49, 0x1e, 0x34, 0xc8, 0x00, 0x36, 0xdc, 0xf8, 0x77, 0x34, 0x8c, 0xfd,
-	0xd0, 0x23, 0x41, 0x9c, 0xcc, 0x13, 0x19, 0x3d, 0x81, 0xb2, 0xa8, 0x18, 0xd0, 0x50, 0x0c, 0x62,
-	0x7a, 0x25, 0x71, 0x3e, 0x0a, 0xd1, 0x53, 0xa8, 0x9c, 0xd1, 0x21, 0x19, 0x88, 0xe1, 0x4d, 0x39,
-	0x64, 0x1a, 0xf8, 0x92, 0xea, 0xf5, 0x0e, 0x36, 0x67, 0x1b, 0xa8, 0x41, 0xeb, 0x50, 0x0e, 0xe2,
-	0x88, 0x93, 0x88, 0x33, 0xd1, 0xa7, 0xea, 0x4d, 0xce, 0xf8, 0xbb, 0x78, 0x10, 0x59, 0xd0, 0xf7,
-	0xba, 0x8f, 0x3d, 0x54, 0x53, 0x3c, 0xe2, 0x14, 0xbd, 0x1a, 0xa9, 0x06, 0xe6, 0x28, 0x19, 0xaa,
-	0x07, 0x48, 0x3f, 0xf1, 0x0f, 0x58, 0x73, 0x38, 0xf7, 0x83, 0x8b, 0xbb, 0xd5, 0xc9, 0x74, 0x33,
-	0xb2, 0xdd, 0x32, 0x17, 0x35, 0x67, 0x2e, 0x7a, 0x08, 0xeb, 0x59, 0x7e, 0x35, 0xc9, 0x2e, 0x58,
-	0x89, 0x88, 0xa8, 0x67, 0xfc, 0x3f, 0xf7, 0x8c, 0xaa, 0x40, 0xc1, 0xf0, 0x5f, 0x03, 0x8a, 0xe2,
-	0x65, 0x73, 0xb3, 0x8d, 0xd7, 0xca, 0x98, 0xb3, 0x56, 0x66, 0x76, 0xad, 0xd0, 0x0e, 0x2c, 0x9d,
-	0x8d, 0x86, 0xc3, 0xc1, 0x04, 0xb0, 0x20, 0x00, 0xd5, 0x34, 0xe8, 0x8d, 0x41, 0xef, 0xc1, 0x62,
-	0xdc, 0xe7, 0x23, 0x66, 0x17, 0x1b, 0x85, 0xe6, 0xf2, 0xde, 0x96, 0x7e, 0xcd, 0x5a, 0x3d, 0x01,
-	0xf2, 0x14, 0x18, 0x6d, 0x01, 0x04, 0x09, 0xf1, 0x39, 0x09, 0x07, 0x3e, 0xb7, 0x2d, 0x31, 0x63,
-	0x45, 0x45, 0x1c, 0x9e, 0xa6, 0x59, 0xba, 0xd1, 0x32, 0x5d, 0x92, 0x69, 0x15, 0x71, 0x38, 0x7a,
-	0x06, 0x8b, 0x67, 0x34, 0xa2, 0xec, 0x42, 0xe6, 0xcb, 0x22, 0x0f, 0xe3, 0x90, 0xc3, 0xd1, 0x5b,
-	0x28, 0x49, 0x39, 0x98, 0x5d, 0x11, 0xee, 0x32, 0x57, 0xb6, 0x31, 0x0e, 0x7f, 0x00, 0x4b, 0xce,
-	0x88, 0x16, 0xa1, 0xd4, 0xf6, 0x3a, 0xce, 0x71, 0xc7, 0xad, 0xfd, 0x97, 0x1e, 0x7a, 0xc7, 0x8e,
-	0x97, 0x1e, 0x0a, 0x68, 0x09, 0x2a, 0xbd, 0x7e, 0xbb, 0xdd, 0xe9, 0xb8, 0x1d, 0xb7, 0x66, 0x20,
-	0x00, 0xeb, 0x93, 0x73, 0xd4, 0xed, 0xb8, 0x35, 0x13, 0x9f, 0x83, 0x25, 0x19, 0x1f, 0x6b, 0x37,
-	0xd1, 0x06, 0x58, 0x6c, 0x7f, 0xf0, 0x93, 0xdc, 0x28, 0xdd, 0x8b, 0x6c, 0xff, 0x33, 0xb9, 0xd9,
-	0xfb, 0x53, 0x04, 0xeb, 0x48, 0x5c, 0x01, 0xf5, 0x01, 0x6e, 0x8d, 0x15, 0xe1, 0xdc, 0x15, 0x73,
-	0x56, 0x5c, 0xdf, 0xb9, 0x13, 0xa3, 0x56, 0xee, 0x2b, 0x94, 0xc7, 0x36, 0x8a, 0x1a, 0xb9, 0x82,
-	0x19, 0x13, 0xae, 0x6f, 0xdf, 0x81, 0x50, 0x84, 0x27, 0x50, 0x9d, 0xb6, 0x4a, 0xf4, 0x5c, 0x57,
-	0x32, 0x6b, 0xba, 0xf5, 0x17, 0xf7, 0xa0, 0x14, 0x79, 0x1f, 0xe0, 0xd6, 0xfc, 0x34, 0x22, 0xe4,
-	0xec, 0x55, 0x23, 0x82, 0xc6, 0x3d, 0x7d, 0x58, 0xce, 0xda, 0x15, 0x7a, 0x99, 0x2b, 0xd3, 0x1a,
-	0x66, 0xfd, 0xd5, 0xbd, 0xb8, 0x8c, 0x2c, 0x13, 0xf3, 0xd1, 0xcb, 0x32, 0x6b, 0x7d, 0x7a, 0x59,
-	0xf2, 0x0e, 0x76, 0x02, 0xd5, 0x69, 0x3f, 0xd1, 0x90, 0x6b, 0xec, 0x4c, 0x43, 0xae, 0x33, 0xa5,
-	0x83, 0xf2, 0x37, 0x4b, 0xa6, 0x4f, 0x2d, 0xf1, 0x23, 0xb0, 0xff, 0x2f, 0x00, 0x00, 0xff, 0xff,
-	0x54, 0x90, 0x0c, 0x6a, 0x24, 0x08, 0x00, 0x00,
+	// 2040 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0x5b, 0x6f, 0xdb, 0xc8,
+	0x15, 0xae, 0x24, 0x8b, 0x12, 0x8f, 0x6f, 0xf2, 0xf8, 0x12, 0x2e, 0xd3, 0xb4, 0x36, 0x1d, 0xc7,
+	0x5e, 0xa0, 0x70, 0x10, 0x67, 0xd3, 0xed, 0x15, 0xa8, 0x6c, 0x29, 0x59, 0xa3, 0xde, 0x75, 0x4a,
+	0xdb, 0x45, 0xd1, 0xa2, 0x2b, 0xd0, 0xe2, 0xc8, 0x26, 0x96, 0x22, 0xb5, 0x33, 0x23, 0x75, 0x9d,
+	0x5f, 0xd2, 0xb7, 0xfe, 0x90, 0x02, 0x7d, 0x2a, 0xd0, 0xff, 0xd0, 0x9f, 0xd0, 0xe7, 0xa2, 0x2f,
+	0x7d, 0x59, 0xcc, 0x85, 0x77, 0x4a, 0x72, 0x9c, 0xbc, 0x69, 0xce, 0x7c, 0x33, 0xe7, 0xc2, 0x73,
+	0xce, 0x9c, 0x73, 0x04, 0x06, 0x19, 0xf5, 0x9f, 0x7b, 0x43, 0xe7, 0x06, 0xd3, 0xe7, 0x14, 0x93,
+	0x89, 0xd7, 0xc7, 0x87, 0x23, 0x12, 0xb2, 0x10, 0xad, 0x32, 0xe2, 0x4c, 0x3c, 0xda, 0xf7, 0x0e,
+	0xe5, 0xb6, 0xf5, 0xff, 0x2a, 0xac, 0x9d, 0x79, 0x94, 0x1d, 0x8f, 0x3d, 0xdf, 0xa5, 0x36, 0xfe,
+	0x76, 0x8c, 0x29, 0x43, 0x8f, 0x41, 0x1f, 0x39, 0x37, 0xb8, 0x47, 0xbd, 0x77, 0xd8, 0xa8, 0x6c,
+	0x57, 0x0e, 0xea, 0x76, 0x93, 0x13, 0x2e, 0xbc, 0x77, 0x18, 0x3d, 0x01, 0x10, 0x9b, 0x2c, 0xfc,
+	0x06, 0x07, 0x46, 0x75, 0xbb, 0x72, 0xa0, 0xdb, 0x02, 0x7e, 0xc9, 0x09, 0x08, 0xc1, 0x42, 0xe0,
+	0x0c, 0xb1, 0x51, 0x13, 0x1b, 0xe2, 0x37, 0xfa, 0x39, 0x34, 0x29, 0x73, 0xd8, 0x98, 0x62, 0x6a,
+	0x2c, 0x6c, 0xd7, 0x0e, 0x56, 0x8e, 0x9e, 0x1c, 0xe6, 0x24, 0x39, 0x14, 0x12, 0x1c, 0x5e, 0x08,
+	0x98, 0x1d, 0xc3, 0x91, 0x09, 0x4d, 0x82, 0x27, 0x1e, 0xf5, 0xc2, 0xc0, 0xa8, 0x8b, 0x2b, 0xe3,
+	0x35, 0xda, 0x85, 0xe5, 0xc1, 0xd8, 0xf7, 0x7b, 0x31, 0x40, 0x13, 0x80, 0x25, 0x4e, 0xb4, 0x53,
+	0xa0, 0x3e, 0xc1, 0x0e, 0xc3, 0x6e, 0xcf, 0x19, 0x30, 0x4c, 0x8c, 0xc6, 0x76, 0xe5, 0xa0, 0x66,
+	0x2f, 0x29, 0x62, 0x9b, 0xd3, 0xd0, 0x1e, 0xac, 0x44, 0xa0, 0x6b, 0x3c, 0x08, 0x09, 0x36, 0x9a,
+	0x02, 0x15, 0x1d, 0x3d, 0x16, 0x44, 0x0e, 0x1b, 0x78, 0x81, 0x47, 0x6f, 0xe3, 0xcb, 0x74, 0x09,
+	0x8b, 0xa8, 0xf2, 0xb6, 0x7d, 0x58, 0x8d, 0x61, 0xea, 0x3a, 0x10, 0xb8, 0xf8, 0xb4, 0xbc, 0xcf,
+	0xf2, 0x01, 0xa5, 0x8d, 0x4f, 0x47, 0x61, 0x40, 0x31, 0x3a, 0x04, 0xed, 0x5a, 0x50, 0x8c, 0xca,
+	0x76, 0xed, 0x60, 0xf1, 0x68, 0xab, 0xdc, 0x56, 0xb6, 0x42, 0xa1, 0x67, 0xb0, 0x1a, 0xe0, 0xef,
+	0x58, 0xaf, 0xf0, 0x55, 0x96, 0x39, 0xf9, 0x6d, 0xf4, 0x65, 0xac, 0x1d, 0x58, 0x7d, 0x83, 0x25,
+	0xb3, 0xe8, 0x43, 0xaf, 0x40, 0xd5, 0x73, 0xc5, 0x17, 0xae, 0xd9, 0x55, 0xcf, 0xb5, 0x7e, 0x03,
+	0xad, 0x04, 0xa2, 0xc4, 0xf9, 0x09, 0xd4, 0x05, 0x23, 0x01, 0x9b, 0x2e, 0x8d, 0x04, 0x59, 0x9f,
+	0xc2, 0xfa, 0x1b, 0xcc, 0xce, 0x1c, 0x9a, 0x65, 0x14, 0x79, 0x45, 0x25, 0xf1, 0x0a, 0xab, 0x03,
+	0x1b, 0x59, 0xe8, 0x83, 0x18, 0xfe, 0xb5, 0x0a, 0x6b, 0x17, 0xcc, 0x21, 0x73, 0xf9, 0x65, 0x5c,
+	0xa9, 0x9a, 0x73, 0xa5, 0x73, 0xd0, 0x27, 0x0e, 0xf1, 0x9c, 0x6b, 0x1f, 0x53, 0xa3, 0x26, 0xcc,
+	0xfe, 0xa2, 0xc0, 0xb7, 0xc0, 0xe6, 0xf0, 0xf7, 0xd1, 0x99, 0x6e, 0xc0, 0xc8, 0x9d, 0x9d, 0xdc,
+	0xc1, 0x43, 0x68, 0xe2, 0x90, 0xde, 0xc0, 0xf3, 0x95, 0xcf, 0xeb, 0x76, 0x73, 0xe2, 0x90, 0xd7,
+	0x7c, 0xcd, 0x1d, 0x84, 0x79, 0x43, 0x1c, 0x8e, 0x59, 0x8f, 0xe2, 0x7e, 0x18, 0xb8, 0x54, 0xf8,
+	0x76, 0xcd, 0x5e, 0x51, 0xe4, 0x0b, 0x49, 0x35, 0x7f, 0x05, 0x2b, 0x59, 0x16, 0xa8, 0x05, 0xb5,
+	0x6f, 0xf0, 0x9d, 0xd2, 0x8b, 0xff, 0x44, 0x1b, 0x50, 0x9f, 0x38, 0xfe, 0x18, 0x2b, 0x9d, 0xe4,
+	0xe2, 0x17, 0xd5, 0x9f, 0x55, 0xac, 0x63, 0x40, 0x69, 0x91, 0x1f, 0x64, 0xde, 0xa7, 0x80, 0x4e,
+	0x9c, 0xa0, 0x8f, 0xfd, 0x99, 0x7e, 0x73, 0x02, 0xeb, 0x19, 0xd4, 0x83, 0x58, 0xed, 0xc2, 0x9a,
+	0x8d, 0x19, 0xb9, 0x9b, 0xc9, 0xe9, 0x18, 0x50, 0x1a, 0xf4, 0x20, 0x46, 0x5d, 0x78, 0x74, 0x11,
+	0x7b, 0xb9, 0x8f, 0x1d, 0x8a, 0xa7, 0xb1, 0x93, 0x3e, 0x23, 0x21, 0xc2, 0xbe, 0x4d, 0x3b, 0x5e,
+	0x5b, 0x5f, 0x80, 0x51, 0xbc, 0xe6, 0x41, 0x02, 0x7d, 0x01, 0xeb, 0x97, 0x8e, 0x27, 0x8d, 0x77,
+	0x16, 0xde, 0x44, 0xc2, 0x7c, 0x02, 0x4d, 0xb1, 0xdf, 0x8b, 0x45, 0x6a, 0x88, 0xf5, 0xa9, 0x8b,
+	0xb6, 0x40, 0x0b, 0x07, 0x03, 0x8a, 0x99, 0x90, 0xaa, 0x66, 0xab, 0x95, 0xe5, 0xc1, 0x46, 0xf6,
+	0x26, 0x25, 0xcf, 0x06, 0xd4, 0x7d, 0x2f, 0xc0, 0x54, 0x39, 0x8e, 0x5c, 0xa0, 0x1f, 0xc3, 0xa2,
+	0xc8, 0x1c, 0x99, 0xab, 0x80, 0x93, 0xce, 0x05, 0x85, 0xab, 0xdf, 0x0f, 0x87, 0x23, 0x1f, 0x33,
+	0x99, 0xd0, 0x9b, 0x76, 0xbc, 0xb6, 0x7a, 0xb0, 0xd9, 0x09, 0xff, 0x12, 0xf8, 0xa1, 0xe3, 0xda,
+	0xb8, 0x1f, 0x92, 0xa9, 0x36, 0x4c, 0xab, 0x51, 0xcd, 0xaa, 0xf1, 0x18, 0x74, 0x1e, 0x21, 0xbd,
+	0xd4, 0x8b, 0xd1, 0xe4, 0x84, 0xaf, 0x78, 0x7e, 0xf8, 0x0c, 0xb6, 0xf2, 0x0c, 0x94, 0x36, 0x42,
+	0xac, 0x80, 0xe1, 0x80, 0x49, 0x85, 0x96, 0xec, 0x78, 0x6d, 0xfd, 0x59, 0x24, 0x20, 0x79, 0xe0,
+	0xca, 0x3e, 0xfb, 0xd8, 0x42, 0x1d, 0xc0, 0x46, 0xf6, 0x7a, 0x25, 0x52, 0x0b, 0x6a, 0x63, 0xe2,
+	0x47, 0x71, 0x39, 0x26, 0xbe, 0xf5, 0x35, 0xac, 0xb7, 0x19, 0x73, 0xfa, 0xb7, 0xb3, 0xad, 0x93,
+	0xe1, 0x56, 0xcd, 0x72, 0xcb, 0x28, 0x5a, 0xcb, 0x29, 0xfa, 0x06, 0x36, 0xb2, 0xf7, 0x2b, 0x49,
+	0x9e, 0x83, 0x46, 0x04, 0x45, 0xf9, 0xde, 0xa3, 0x82, 0xef, 0xa9, 0x03, 0x0a, 0x66, 0x8d, 0x60,
+	0xf3, 0x44, 0x3c, 0x73, 0x17, 0xfd, 0x5b, 0xec, 0x8e, 0x7d, 0xfc, 0xd0, 0x24, 0xba, 0x0f, 0xab,
+	0x7d, 0x12, 0x06, 0x3d, 0xfc, 0xdd, 0x88, 0x60, 0x2a, 0x20, 0xd2, 0x7c, 0x2b, 0x9c, 0xdc, 0x8d,
+	0xa9, 0xd6, 0x39, 0x6c, 0xe5, 0x39, 0x2a, 0xe1, 0x5f, 0x41, 0x93, 0x2a, 0x9a, 0x12, 0xff, 0x93,
+	0x62, 0x1a, 0x8e, 0x0e, 0xc5, 0x50, 0x6b, 0x0b, 0x36, 0xf8, 0x43, 0x1a, 0xed, 0x44, 0x85, 0x8c,
+	0xf5, 0x16, 0x36, 0x73, 0x74, 0xc5, 0xe7, 0x73, 0xd0, 0xa3, 0xc3, 0xd1, 0x33, 0x3b, 0x83, 0x51,
+	0x82, 0xb5, 0xf6, 0x61, 0xb3, 0x83, 0x7d, 0x5c, 0x34, 0x56, 0x3e, 0x51, 0x19, 0xb0, 0x95, 0x07,
+	0x4a, 0xde, 0xd6, 0xff, 0x2a, 0xd0, 0x8c, 0x88, 0x05, 0x77, 0x88, 0x6c, 0x5e, 0x9d, 0x62, 0xf3,
+	0xda, 0x7c, 0x9b, 0x2f, 0x94, 0xd9, 0x1c, 0xfd, 0x48, 0xc5, 0x3a, 0x19, 0x07, 0x3d, 0x87, 0xa9,
+	0xf7, 0x46, 0xe7, 0x24, 0x7b, 0x1c, 0xb4, 0x19, 0xdf, 0xf7, 0x1d, 0x1a, 0xef, 0x6b, 0x72, 0x9f,
+	0x93, 0xe4, 0xbe, 0x05, 0xcb, 0x62, 0x3f, 0x8e, 0x1a, 0x59, 0x47, 0x89, 0x43, 0xc7, 0x2a, 0x72,
+	0x9e, 0x00, 0xc4, 0xb5, 0x16, 0x53, 0x25, 0x94, 0x1e, 0x15, 0x5a, 0xcc, 0xfa, 0x4f, 0x05, 0xb6,
+	0xf8, 0xe7, 0x68, 0x8f, 0x5d, 0x8f, 0x75, 0x27, 0xdc, 0x8b, 0x3f, 0x46, 0xc5, 0xf9, 0x43, 0xd0,
+	0x47, 0xc4, 0x0b, 0xfa, 0xde, 0xc8, 0xf1, 0x95, 0x7d, 0x12, 0x02, 0xcf, 0x94, 0x43, 0xcc, 0x6e,
+	0x43, 0x57, 0xd9, 0x45, 0xad, 0xb8, 0xa1, 0xfb, 0xa1, 0x8b, 0x55, 0x51, 0x29, 0x7e, 0x17, 0x6b,
+	0x45, 0xed, 0x5e, 0xb5, 0x62, 0xa3, 0xa4, 0x56, 0xb4, 0x26, 0xf0, 0xa8, 0xa0, 0xab, 0x72, 0xbe,
+	0x97, 0xa0, 0xe1, 0x89, 0x4a, 0x5e, 0xdc, 0xf3, 0x1e, 0x17, 0x3c, 0x2f, 0x39, 0x65, 0x2b, 0xe8,
+	0xbd, 0xab, 0xbc, 0xbf, 0x55, 0x00, 0x92, 0xe3, 0x05, 0xff, 0xca, 0x18, 0xab, 0x3a, 0xdd, 0x58,
+	0xb5, 0x8c, 0xb1, 0x0c, 0x68, 0xd0, 0xf1, 0x70, 0xe8, 0x90, 0x3b, 0x65, 0xc5, 0x68, 0x59, 0x6a,
+	0xc6, 0xac, 0x1b, 0x68, 0x79, 0x37, 0x38, 0x92, 0xc1, 0x7a, 0x89, 0x87, 0x23, 0xdf, 0x61, 0x71,
+	0xb0, 0x66, 0xdc, 0xbc, 0x92, 0x75, 0x73, 0xeb, 0x9f, 0x15, 0xd8, 0xcc, 0x1d, 0x4a, 0x22, 0x99,
+	0x45, 0xc4, 0xa9, 0x91, 0x1c, 0x1d, 0xb3, 0x13, 0x6c, 0xb1, 0x7b, 0xa8, 0x96, 0x74, 0x0f, 0x5f,
+	0xc2, 0x9a, 0x17, 0x4c, 0x1c, 0xdf, 0x73, 0x7b, 0x09, 0x17, 0x59, 0x1f, 0x6e, 0x17, 0xb8, 0x9c,
+	0x4a, 0x64, 0xcc, 0xac, 0xe5, 0x65, 0x09, 0xd4, 0xfa, 0x25, 0xac, 0xe6, 0x40, 0xa5, 0x49, 0x76,
+	0x03, 0xea, 0x98, 0x90, 0x90, 0x44, 0x25, 0x9d, 0x58, 0x58, 0x1d, 0x40, 0x6f, 0x70, 0x6c, 0x81,
+	0x07, 0x26, 0x69, 0xeb, 0x5b, 0x58, 0xcf, 0xdc, 0x92, 0x24, 0xde, 0x48, 0xc1, 0xa9, 0x89, 0x37,
+	0x3e, 0x14, 0x43, 0xef, 0x65, 0x44, 0xeb, 0xef, 0x35, 0x68, 0xce, 0xd4, 0xf7, 0xd7, 0xea, 0x31,
+	0xc6, 0x84, 0x1a, 0x55, 0x61, 0xdc, 0x9d, 0xa9, 0xcc, 0x65, 0xe5, 0x84, 0x89, 0x1d, 0x1f, 0x41,
+	0xaf, 0x8b, 0xc5, 0xfb, 0xc1, 0xf4, 0xf3, 0xd3, 0x6b, 0xf6, 0x53, 0x58, 0x1a, 0x91, 0x50, 0x0a,
+	0x8d, 0x89, 0x2c, 0xdb, 0x17, 0x8f, 0xf6, 0xa6, 0x5f, 0xf5, 0x36, 0x41, 0xdb, 0x99, 0xa3, 0xe8,
+	0x15, 0x40, 0x92, 0x2d, 0x8d, 0xfa, 0xcc, 0x22, 0x50, 0x8f, 0x53, 0xa8, 0xf9, 0x02, 0x1a, 0x4a,
+	0xbd, 0x52, 0x3b, 0x21, 0x58, 0x60, 0x77, 0xa3, 0xf8, 0x71, 0xe0, 0xbf, 0xcd, 0x1d, 0x58, 0x4c,
+	0x89, 0x11, 0x43, 0x2a, 0x29, 0xc8, 0x87, 0x75, 0x11, 0xff, 0xd6, 0xa0, 0x2e, 0x84, 0xfa, 0xe0,
+	0xb7, 0xaa, 0xe0, 0x2c, 0x0b, 0x25, 0x11, 0xf7, 0x0a, 0x34, 0xd9, 0xfc, 0x0b, 0xab, 0xcd, 0x9d,
+	0x14, 0x28, 0xf0, 0x9c, 0x9c, 0xc3, 0xb7, 0x29, 0x73, 0x88, 0xda, 0x96, 0x09, 0x5b, 0x57, 0x94,
+	0x36, 0xe3, 0x85, 0x70, 0xd2, 0xd8, 0x47, 0x2f, 0x17, 0xc4, 0x5d, 0x3d, 0x43, 0x2f, 0xa0, 0x21,
+	0xab, 0x25, 0x6a, 0xe8, 0xdb, 0xb5, 0x59, 0x55, 0x55, 0x84, 0x13, 0xc3, 0x02, 0xc7, 0xf3, 0xc7,
+	0x04, 0xf7, 0x08, 0x76, 0x68, 0x18, 0x88, 0x21, 0x80, 0x6e, 0x2f, 0x2b, 0xaa, 0x2d, 0x88, 0xe8,
+	0x24, 0xed, 0xbc, 0x8b, 0x53, 0x3c, 0x4e, 0xaa, 0x7c, 0xcf, 0x6e, 0x73, 0x29, 0xd7, 0x6d, 0x7e,
+	0x0e, 0xba, 0x43, 0x98, 0x37, 0x70, 0xfa, 0x8c, 0x1a, 0xcb, 0x53, 0x32, 0x64, 0x5b, 0x21, 0xec,
+	0x04, 0x5b, 0xd6, 0xa6, 0xae, 0x94, 0xb5, 0xa9, 0xe8, 0x33, 0x68, 0x3a, 0x8c, 0xe7, 0x04, 0x46,
+	0x8d, 0x55, 0xc1, 0xc0, 0x28, 0x32, 0x90, 0x00, 0x3b, 0x46, 0xa2, 0x1d, 0x58, 0x22, 0x98, 0x11,
+	0x0f, 0xbb, 0xbd, 0x01, 0x09, 0x87, 0x46, 0x4b, 0x16, 0x14, 0x8a, 0xf6, 0x9a, 0x84, 0xc3, 0xec,
+	0x6b, 0xb5, 0x96, 0x7f, 0xad, 0xd2, 0xcd, 0x19, 0xca, 0x36, 0x67, 0x1f, 0xe8, 0xf3, 0x37, 0xa0,
+	0x49, 0xff, 0x42, 0x8b, 0xd0, 0x38, 0xb1, 0xbb, 0xed, 0xcb, 0x6e, 0xa7, 0xf5, 0x03, 0xbe, 0xb8,
+	0xb8, 0x6c, 0xdb, 0x7c, 0x51, 0x41, 0xcb, 0xa0, 0x5f, 0x5c, 0x9d, 0x9c, 0x74, 0xbb, 0x9d, 0x6e,
+	0xa7, 0x55, 0x45, 0x00, 0xda, 0xeb, 0xf6, 0xe9, 0x59, 0xb7, 0xd3, 0xaa, 0xf1, 0xdf, 0xbf, 0xbb,
+	0xea, 0x5e, 0x75, 0x3b, 0xad, 0x05, 0x0e, 0x3b, 0x69, 0x7f, 0x75, 0xd2, 0x3d, 0xe3, 0x5b, 0x75,
+	0xbe, 0xbc, 0x3c, 0xfd, 0xb2, 0xdb, 0xe9, 0x9d, 0x5f, 0x5d, 0xb6, 0x34, 0x5e, 0x12, 0x35, 0x94,
+	0x65, 0xde, 0xa7, 0x45, 0xd9, 0x02, 0x2d, 0x18, 0x0f, 0xaf, 0x31, 0x11, 0x31, 0x56, 0xb7, 0xd5,
+	0x2a, 0x15, 0x3c, 0x0b, 0xef, 0x19, 0x3c, 0xa9, 0xe8, 0xa8, 0xcf, 0x89, 0x0e, 0xad, 0x10, 0x1d,
+	0x45, 0x57, 0x6f, 0x94, 0xb8, 0xba, 0xf5, 0xaf, 0x0a, 0x34, 0x23, 0x3f, 0x7b, 0x1f, 0x6d, 0x0d,
+	0x68, 0xa8, 0x5c, 0xaf, 0x52, 0x4a, 0xb4, 0xe4, 0x82, 0xab, 0x9f, 0xfc, 0x98, 0x4c, 0x27, 0xba,
+	0xa2, 0x9c, 0xba, 0x5c, 0xf0, 0xc8, 0x9b, 0xf9, 0xbe, 0xac, 0x51, 0x20, 0x22, 0x9d, 0xba, 0x68,
+	0x1b, 0x16, 0x5d, 0x4c, 0xfb, 0xc4, 0x1b, 0xb1, 0x64, 0x7e, 0x98, 0x26, 0x71, 0x1f, 0x91, 0x51,
+	0xd5, 0x10, 0x51, 0x25, 0x17, 0xd6, 0x7f, 0x2b, 0xa0, 0xc9, 0x78, 0xff, 0x58, 0x8d, 0x25, 0xda,
+	0x04, 0x8d, 0xbe, 0xec, 0x71, 0x0f, 0x95, 0x6a, 0xd4, 0xe9, 0xcb, 0xdf, 0xe2, 0x3b, 0xae, 0xa1,
+	0x0a, 0x98, 0x48, 0x83, 0x9a, 0xad, 0x2b, 0x8a, 0x74, 0x04, 0x7a, 0xeb, 0x1c, 0xbd, 0xfa, 0xa9,
+	0x92, 0x5d, 0xad, 0x78, 0x6a, 0x16, 0xa5, 0xb4, 0xcc, 0x74, 0xe2, 0x37, 0x8f, 0x37, 0xd5, 0x3c,
+	0xf6, 0xc4, 0x13, 0xd1, 0x94, 0xda, 0x2a, 0xda, 0xe5, 0xdd, 0x28, 0x5f, 0xb9, 0xe9, 0xb9, 0x2c,
+	0x7a, 0xf4, 0x8f, 0x25, 0xd0, 0x4e, 0x85, 0x1f, 0xa1, 0x2b, 0x80, 0x64, 0x74, 0x89, 0xac, 0x82,
+	0x9f, 0x15, 0x86, 0xca, 0xe6, 0xee, 0x4c, 0x8c, 0x2a, 0x43, 0xce, 0xa1, 0x19, 0x0d, 0x20, 0x51,
+	0xb1, 0xc0, 0xca, 0x8d, 0x2f, 0xcd, 0x9d, 0x19, 0x08, 0x75, 0xe1, 0x9f, 0x60, 0x29, 0x3d, 0x64,
+	0x44, 0x4f, 0xcb, 0x8e, 0xe4, 0xc7, 0x95, 0xe6, 0xde, 0x1c, 0x94, 0xba, 0xfc, 0x0a, 0x20, 0x19,
+	0xb0, 0x95, 0x18, 0xa1, 0x30, 0x30, 0x34, 0x77, 0x67, 0x62, 0xd4, 0xb5, 0x7f, 0x80, 0xc5, 0xd4,
+	0x34, 0x0d, 0x15, 0xcf, 0x14, 0x27, 0x72, 0xe6, 0xd3, 0xd9, 0xa0, 0x44, 0xe0, 0x64, 0x7a, 0x56,
+	0x22, 0x70, 0x61, 0xfe, 0x66, 0xee, 0xce, 0xc4, 0xa8, 0x6b, 0x6f, 0xa0, 0x95, 0x9f, 0x84, 0xa1,
+	0x62, 0x05, 0x36, 0x65, 0xe6, 0x66, 0x7e, 0x7a, 0x0f, 0x64, 0xf2, 0x35, 0xd3, 0xe3, 0xad, 0x92,
+	0xaf, 0x59, 0x32, 0x47, 0x33, 0xf7, 0xe6, 0xa0, 0xd4, 0xe5, 0x0e, 0xac, 0x64, 0xa7, 0x12, 0xe8,
+	0x59, 0xd1, 0xa8, 0x65, 0x83, 0x12, 0x73, 0x7f, 0x2e, 0x4e, 0xb1, 0xf8, 0x1a, 0x96, 0x33, 0xf3,
+	0x08, 0xb4, 0x57, 0x1a, 0x14, 0xf9, 0x39, 0x86, 0xf9, 0x6c, 0x1e, 0x2c, 0x51, 0x21, 0x3b, 0x74,
+	0x28, 0x51, 0xa1, 0x74, 0x7c, 0x61, 0xee, 0xcf, 0xc5, 0x29, 0x16, 0x2e, 0xac, 0xe6, 0xfa, 0x5a,
+	0xb4, 0x5f, 0x2a, 0x5d, 0xb1, 0xcb, 0x37, 0x0f, 0xe6, 0x03, 0xb3, 0x86, 0x8a, 0x3b, 0xa7, 0x29,
+	0x86, 0xca, 0xf7, 0x90, 0xe6, 0xb3, 0x79, 0xb0, 0x24, 0xc4, 0x52, 0x5d, 0x50, 0x49, 0x88, 0x15,
+	0x3b, 0x2d, 0xf3, 0xe9, 0x6c, 0x50, 0xea, 0x13, 0x64, 0xa6, 0x96, 0x65, 0x9f, 0xa0, 0x6c, 0x6e,
+	0x6a, 0xee, 0xcf, 0xc5, 0x65, 0x72, 0x5a, 0x3c, 0x83, 0x2c, 0xcf, 0x69, 0xf9, 0x09, 0xa8, 0xb9,
+	0x37, 0x07, 0x95, 0x5c, 0x9e, 0x1e, 0x2b, 0x96, 0x5c, 0x5e, 0x32, 0xd5, 0x34, 0xf7, 0xe6, 0xa0,
+	0xe4, 0xe5, 0xc7, 0xcd, 0x3f, 0x6a, 0x72, 0xfb, 0x5a, 0x13, 0x7f, 0x48, 0xbe, 0xfc, 0x7e, 0x00,
+	0x63, 0xce, 0xdf, 0x48, 0xac, 0x1c, 0x00, 0x00,
 }
@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr  string
+		valid bool
+	}{
+		{"* * * * *", true},
+		{"*/15 0-6 1,15 * mon-fri", true},
+		{"0 12 * JAN,jul sun", true},
+		{"5/15 * * * *", true},
+		{"0 0 * * 7", true},
+		{"@daily", true},
+		{"@HOURLY", true},
+		{"  0 0 1 1 *  ", true},
+		{"", false},
+		{"* * * *", false},
+		{"* * * * * *", false},
+		{"60 * * * *", false},
+		{"* 24 * * *", false},
+		{"* * 0 * *", false},
+		{"* * 32 * *", false},
+		{"* * * 13 *", false},
+		{"* * * * 8", false},
+		{"*/0 * * * *", false},
+		{"*/x * * * *", false},
+		{"5-1 * * * *", false},
+		{"a * * * *", false},
+		{"* * * foo *", false},
+		{"@every 5m", false},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.expr)
+		if tt.valid && err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.expr, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("Parse(%q) should have returned an error", tt.expr)
+		}
+	}
+}
+
+func TestExpressionNext(t *testing.T) {
+	// 2019-01-01 was a Tuesday
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2019, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"*/15 * * * *", at(1, 1, 10, 7), at(1, 1, 10, 15)},
+		{"0 * * * *", at(1, 1, 10, 0), at(1, 1, 11, 0)},
+		{"5/15 * * * *", at(1, 1, 10, 21), at(1, 1, 10, 35)},
+		{"30 9 * * mon-fri", at(1, 5, 12, 0), at(1, 7, 9, 30)},
+		{"0 0 * * 7", at(1, 1, 0, 0), at(1, 6, 0, 0)},
+		{"0 12 13 * 5", at(1, 1, 0, 0), at(1, 4, 12, 0)},
+		{"0 0 1 */3 *", at(2, 10, 0, 0), at(4, 1, 0, 0)},
+		{"@yearly", at(3, 5, 0, 0), time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", at(1, 1, 0, 0), time.Time{}},
+	}
+
+	for _, tt := range tests {
+		e, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+
+		if got := e.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("Next(%v) for %q = %v, want %v", tt.from, tt.expr, got, tt.want)
+		}
+	}
+}
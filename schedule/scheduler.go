@@ -0,0 +1,105 @@
+package schedule
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"github.com/travis-ci/imaged/auth"
+	"github.com/travis-ci/imaged/db"
+	pb "github.com/travis-ci/imaged/rpc/images"
+	"strconv"
+	"time"
+)
+
+const defaultPollInterval = 30 * time.Second
+
+// Starter starts builds. It's implemented by the API server, so scheduled
+// builds go through the same checks as builds started through the API.
+type Starter interface {
+	StartBuild(ctx context.Context, req *pb.StartBuildRequest) (*pb.StartBuildResponse, error)
+}
+
+// Scheduler starts builds for schedules when they're due.
+//
+// Several imaged instances can run schedulers against the same database, and
+// each run of a schedule is claimed by only one of them. A run that fails to
+// start a build is not retried, so a schedule never starts more than one build
+// for the same run.
+type Scheduler struct {
+	DB      *db.Connection
+	Starter Starter
+	// PollInterval is how often the scheduler checks for due schedules.
+	PollInterval time.Duration
+}
+
+// Run starts builds for due schedules until the process exits.
+//
+// It should be called in a goroutine.
+func (s *Scheduler) Run() {
+	interval := s.PollInterval
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	for {
+		if !s.runNext(context.Background()) {
+			time.Sleep(interval)
+		}
+	}
+}
+
+// runNext starts a build for a single due schedule.
+//
+// Returns false if there were no due schedules.
+func (s *Scheduler) runNext(ctx context.Context) bool {
+	schedule, err := s.DB.ClaimDueSchedule(ctx, func(sc *db.Schedule) time.Time {
+		return NextRun(sc.CronExpression, time.Now().UTC())
+	})
+	if err != nil {
+		log.WithError(err).Error("could not claim a due schedule")
+		return false
+	}
+
+	if schedule == nil {
+		return false
+	}
+
+	l := log.WithFields(log.Fields{
+		"schedule_id": schedule.ID,
+		"name":        schedule.Name,
+		"revision":    schedule.Revision,
+	})
+
+	// Save the schedule as who started the build
+	ctx = auth.WithPrincipal(ctx, "schedule:"+strconv.FormatInt(schedule.ID, 10))
+	resp, err := s.Starter.StartBuild(ctx, &pb.StartBuildRequest{
+		Name:     schedule.Name,
+		Revision: schedule.Revision,
+	})
+	if err != nil {
+		l.WithError(err).Error("could not start scheduled build")
+		return true
+	}
+
+	build := &db.Build{ID: resp.Build.Id}
+	if err = s.DB.SetScheduleLastBuild(ctx, schedule, build); err != nil {
+		l.WithError(err).Error("could not save scheduled build")
+	}
+
+	l.WithField("build_id", build.ID).Info("started scheduled build")
+	return true
+}
+
+// NextRun returns the next time after t that a cron expression matches.
+//
+// An invalid expression, or one that never matches, is pushed far into the
+// future so that it doesn't run over and over.
+func NextRun(expr string, t time.Time) time.Time {
+	e, err := Parse(expr)
+	if err == nil {
+		if next := e.Next(t); !next.IsZero() {
+			return next
+		}
+	}
+
+	return t.AddDate(maxSearchYears, 0, 0)
+}
@@ -0,0 +1,200 @@
+package secrets
+
+import (
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Installation is the set of secret files installed for a build.
+//
+// Only what the installation changed is undone by Cleanup: files and
+// directories it created are removed, and files in the checkout that a secret
+// replaced get their old contents back. Directories that were already in the
+// checkout are left in place.
+type Installation struct {
+	created  []string
+	replaced map[string]*original
+}
+
+// original is the contents of a file in the checkout before a secret
+// replaced it.
+type original struct {
+	contents []byte
+	mode     os.FileMode
+}
+
+// Install copies a template's secret files into a templates checkout.
+//
+// Cleanup should be called once the build is done, even if Install fails, to
+// remove the files that were copied.
+func (c *Config) Install(template string, dir string) (*Installation, error) {
+	inst := &Installation{replaced: make(map[string]*original)}
+	for _, r := range c.rules(template) {
+		for _, f := range r.Files {
+			if err := inst.install(f, dir); err != nil {
+				return inst, err
+			}
+		}
+	}
+
+	return inst, nil
+}
+
+func (inst *Installation) install(f File, dir string) error {
+	dest, err := destination(dir, f.Destination)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(f.Source)
+	if err != nil {
+		if os.IsNotExist(err) && f.Optional {
+			return nil
+		}
+
+		return errors.Wrapf(err, "could not find secret %s", f.Source)
+	}
+
+	if !info.IsDir() {
+		return inst.copyFile(f.Source, dest)
+	}
+
+	return filepath.Walk(f.Source, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(f.Source, p)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, rel)
+		if info.IsDir() {
+			return inst.mkdirAll(target, 0700)
+		}
+
+		return inst.copyFile(p, target)
+	})
+}
+
+// Cleanup removes the secret files that were installed, and puts back the
+// files they replaced.
+func (inst *Installation) Cleanup() error {
+	var firstErr error
+	for i := len(inst.created) - 1; i >= 0; i-- {
+		if err := os.RemoveAll(inst.created[i]); err != nil && firstErr == nil {
+			firstErr = errors.Wrap(err, "could not remove secret")
+		}
+	}
+
+	for p, o := range inst.replaced {
+		if err := restore(p, o); err != nil && firstErr == nil {
+			firstErr = errors.Wrap(err, "could not restore file replaced by secret")
+		}
+	}
+
+	inst.created = nil
+	inst.replaced = make(map[string]*original)
+	return firstErr
+}
+
+// mkdirAll creates a directory and any missing parents, remembering the
+// outermost directory it created so that Cleanup can remove it.
+func (inst *Installation) mkdirAll(dir string, perm os.FileMode) error {
+	missing := ""
+	for p := dir; ; p = filepath.Dir(p) {
+		if _, err := os.Lstat(p); err == nil {
+			break
+		} else if !os.IsNotExist(err) {
+			return errors.Wrap(err, "could not create directory for secret")
+		}
+
+		missing = p
+		if filepath.Dir(p) == p {
+			break
+		}
+	}
+
+	if missing == "" {
+		return nil
+	}
+
+	// Remember the directory before creating it, so that it's removed even if
+	// creating it fails part of the way through
+	inst.created = append(inst.created, missing)
+	return errors.Wrap(os.MkdirAll(dir, perm), "could not create directory for secret")
+}
+
+// copyFile copies a secret into the checkout, remembering whether it created
+// the file or replaced one that was already there.
+func (inst *Installation) copyFile(src string, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrap(err, "could not open secret")
+	}
+	defer in.Close()
+
+	if err = inst.mkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(dest)
+	switch {
+	case os.IsNotExist(err):
+		inst.created = append(inst.created, dest)
+	case err != nil:
+		return errors.Wrap(err, "could not check for existing file")
+	case !info.Mode().IsRegular():
+		return errors.Errorf("secret destination %s is not a regular file", dest)
+	case inst.replaced[dest] == nil:
+		contents, err := ioutil.ReadFile(dest)
+		if err != nil {
+			return errors.Wrap(err, "could not back up file replaced by secret")
+		}
+		inst.replaced[dest] = &original{contents: contents, mode: info.Mode()}
+	}
+
+	// Only new files are created with the right mode, so the secret would
+	// otherwise keep the mode of the file it replaces
+	if err = os.Chmod(dest, 0600); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "could not protect secret file")
+	}
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "could not create secret file")
+	}
+	defer out.Close()
+
+	if _, err = io.Copy(out, in); err != nil {
+		return errors.Wrap(err, "could not copy secret")
+	}
+
+	return out.Close()
+}
+
+func restore(p string, o *original) error {
+	if err := ioutil.WriteFile(p, o.contents, o.mode); err != nil {
+		return err
+	}
+
+	// WriteFile only uses the mode for new files
+	return os.Chmod(p, o.mode)
+}
+
+// destination converts a destination from the config into a path in the
+// checkout, making sure it can't point outside of the checkout.
+func destination(dir string, dest string) (string, error) {
+	clean := path.Clean(dest)
+	if dest == "" || path.IsAbs(dest) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", errors.Errorf("secret destination %q must be a relative path inside the templates repo", dest)
+	}
+
+	return filepath.Join(dir, filepath.FromSlash(clean)), nil
+}
@@ -0,0 +1,141 @@
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDestination(t *testing.T) {
+	tests := []struct {
+		dest string
+		path string
+	}{
+		{"linux_playbooks/secrets.yml", "/checkout/linux_playbooks/secrets.yml"},
+		{"./certs", "/checkout/certs"},
+		{"certs/", "/checkout/certs"},
+		{"a/../b", "/checkout/b"},
+		{"a//b", "/checkout/a/b"},
+		{"..data", "/checkout/..data"},
+		{"", ""},
+		{".", ""},
+		{"a/..", ""},
+		{"..", ""},
+		{"../secrets.yml", ""},
+		{"a/../../secrets.yml", ""},
+		{"/etc/secrets.yml", ""},
+	}
+
+	for _, tt := range tests {
+		p, err := destination("/checkout", tt.dest)
+		if tt.path == "" {
+			if err == nil {
+				t.Errorf("destination(%q) = %q, want an error", tt.dest, p)
+			}
+			continue
+		}
+
+		if err != nil || p != filepath.FromSlash(tt.path) {
+			t.Errorf("destination(%q) = %q, %v, want %q", tt.dest, p, err, tt.path)
+		}
+	}
+}
+
+func TestInstallCleanup(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "imaged-secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	src := filepath.Join(tmp, "secrets")
+	checkout := filepath.Join(tmp, "checkout")
+	writeFile(t, filepath.Join(src, "ansible.yml"), "password: hunter22", 0644)
+	writeFile(t, filepath.Join(src, "certs", "ca.pem"), "ca", 0644)
+	writeFile(t, filepath.Join(src, "certs", "client", "client.pem"), "client", 0644)
+	writeFile(t, filepath.Join(checkout, "linux_playbooks", "site.yml"), "- hosts: all", 0644)
+	writeFile(t, filepath.Join(checkout, "linux_playbooks", "secrets.yml"), "password: placeholder", 0644)
+
+	c := &Config{Rules: []Rule{
+		{
+			Templates: []string{"*"},
+			Files: []File{
+				{Source: filepath.Join(src, "ansible.yml"), Destination: "linux_playbooks/secrets.yml"},
+				{Source: filepath.Join(src, "missing.yml"), Destination: "linux_playbooks/missing.yml", Optional: true},
+			},
+		},
+		{
+			Templates: []string{"macos-*"},
+			Files: []File{
+				{Source: filepath.Join(src, "certs"), Destination: "ansible/files/certs"},
+			},
+		},
+	}}
+
+	inst, err := c.Install("macos-mojave", checkout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installed := []struct {
+		path     string
+		contents string
+	}{
+		{"linux_playbooks/secrets.yml", "password: hunter22"},
+		{"ansible/files/certs/ca.pem", "ca"},
+		{"ansible/files/certs/client/client.pem", "client"},
+	}
+
+	for _, f := range installed {
+		p := filepath.Join(checkout, filepath.FromSlash(f.path))
+		checkFile(t, p, f.contents, 0600)
+	}
+
+	if _, err = os.Stat(filepath.Join(checkout, "linux_playbooks", "missing.yml")); !os.IsNotExist(err) {
+		t.Errorf("optional secret that doesn't exist was installed")
+	}
+
+	if err = inst.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+
+	checkFile(t, filepath.Join(checkout, "linux_playbooks", "site.yml"), "- hosts: all", 0644)
+	checkFile(t, filepath.Join(checkout, "linux_playbooks", "secrets.yml"), "password: placeholder", 0644)
+
+	if _, err = os.Stat(filepath.Join(checkout, "ansible")); !os.IsNotExist(err) {
+		t.Errorf("directory created for secrets was not removed")
+	}
+}
+
+func writeFile(t *testing.T, p string, contents string, mode os.FileMode) {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(p, []byte(contents), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkFile(t *testing.T, p string, contents string, mode os.FileMode) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		t.Errorf("could not read %s: %v", p, err)
+		return
+	}
+
+	if string(b) != contents {
+		t.Errorf("%s contains %q, want %q", p, b, contents)
+	}
+
+	info, err := os.Stat(p)
+	if err != nil {
+		t.Errorf("could not stat %s: %v", p, err)
+		return
+	}
+
+	if info.Mode().Perm() != mode {
+		t.Errorf("%s has mode %v, want %v", p, info.Mode().Perm(), mode)
+	}
+}
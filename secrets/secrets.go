@@ -0,0 +1,349 @@
+// Package secrets decides which secrets each template's builds get, and
+// installs them into the templates checkout for the length of a build.
+//
+// Secrets are configured with a YAML file of rules. Each rule applies to the
+// templates whose names match one of its globs, and can install secret files
+// or directories into the checkout, set Packer user variables, or set
+// environment variables for Packer:
+//
+//	secrets:
+//	- templates: ["*"]
+//	  files:
+//	  - source: /run/secrets/ansible.yml
+//	    destination: linux_playbooks/secrets.yml
+//	- templates: ["macos-*"]
+//	  files:
+//	  - source: /run/secrets/certs
+//	    destination: certs
+//	  variables:
+//	    vsphere_password:
+//	      file: /run/secrets/vsphere_password
+//	  env:
+//	    VAULT_TOKEN:
+//	      env: IMAGED_VAULT_TOKEN
+//
+// Variables and environment variables get their values from either a file or
+// an environment variable of the imaged process.
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Config is the set of rules for which secrets each template gets.
+//
+// A nil config gives no secrets to any template.
+type Config struct {
+	Rules []Rule `json:"secrets"`
+}
+
+// Rule gives secrets to the templates that match it.
+type Rule struct {
+	// Templates are globs that match template names, like "macos-*".
+	Templates []string `json:"templates"`
+	// Files are copied into the templates checkout.
+	Files []File `json:"files"`
+	// Variables are set as Packer user variables.
+	Variables map[string]Source `json:"variables"`
+	// Env are set as environment variables for Packer.
+	Env map[string]Source `json:"env"`
+}
+
+// File is a secret file or directory that is copied into the checkout.
+type File struct {
+	// Source is the path of the file or directory on the imaged host.
+	Source string `json:"source"`
+	// Destination is the path in the templates checkout to copy it to.
+	Destination string `json:"destination"`
+	// Optional skips the file when the source doesn't exist, instead of
+	// failing the build.
+	Optional bool `json:"optional"`
+}
+
+// Source is where the value of a secret variable comes from. Exactly one of
+// its fields should be set.
+type Source struct {
+	// File is the path of a file containing the value. A trailing newline is
+	// removed.
+	File string `json:"file"`
+	// Env is the name of an environment variable containing the value.
+	Env string `json:"env"`
+}
+
+// Load reads and checks a secrets config file.
+func Load(p string) (*Config, error) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read secrets config")
+	}
+
+	var c Config
+	if err = yaml.Unmarshal(b, &c); err != nil {
+		return nil, errors.Wrap(err, "could not parse secrets config")
+	}
+
+	if err = c.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid secrets config")
+	}
+
+	return &c, nil
+}
+
+// AnsibleSecretsFile creates a rule that installs a single Ansible secrets
+// file for every template, the way imaged always has.
+//
+// The file is optional, so templates that don't need it can still be built
+// when it's missing.
+func AnsibleSecretsFile(p string) Rule {
+	return Rule{
+		Templates: []string{"*"},
+		Files: []File{{
+			Source:      p,
+			Destination: "linux_playbooks/secrets.yml",
+			Optional:    true,
+		}},
+	}
+}
+
+func (c *Config) validate() error {
+	for i, r := range c.Rules {
+		if len(r.Templates) == 0 {
+			return errors.Errorf("rule %d does not match any templates", i)
+		}
+
+		for _, t := range r.Templates {
+			if _, err := path.Match(t, ""); err != nil {
+				return errors.Errorf("rule %d has invalid template glob %q", i, t)
+			}
+		}
+
+		for _, f := range r.Files {
+			if f.Source == "" {
+				return errors.Errorf("rule %d has a file without a source", i)
+			}
+
+			if _, err := destination("", f.Destination); err != nil {
+				return errors.Wrapf(err, "rule %d", i)
+			}
+		}
+
+		for name, s := range r.Variables {
+			if (s.File == "") == (s.Env == "") {
+				return errors.Errorf("rule %d variable %s needs either a file or an env", i, name)
+			}
+		}
+
+		for name, s := range r.Env {
+			if (s.File == "") == (s.Env == "") {
+				return errors.Errorf("rule %d env %s needs either a file or an env", i, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// rules returns the rules that apply to a template.
+func (c *Config) rules(template string) []Rule {
+	if c == nil {
+		return nil
+	}
+
+	var rules []Rule
+	for _, r := range c.Rules {
+		for _, t := range r.Templates {
+			if ok, _ := path.Match(t, template); ok {
+				rules = append(rules, r)
+				break
+			}
+		}
+	}
+
+	return rules
+}
+
+// Variables gets the secret Packer user variables for a template.
+func (c *Config) Variables(template string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, r := range c.rules(template) {
+		for name, s := range r.Variables {
+			v, err := s.value()
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not get secret variable %s", name)
+			}
+			vars[name] = v
+		}
+	}
+
+	return vars, nil
+}
+
+// Env gets the secret environment variables for a template, in the
+// "NAME=value" form used by exec.Cmd.
+func (c *Config) Env(template string) ([]string, error) {
+	env := make(map[string]string)
+	for _, r := range c.rules(template) {
+		for name, s := range r.Env {
+			v, err := s.value()
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not get secret env %s", name)
+			}
+			env[name] = v
+		}
+	}
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	vars := make([]string, len(names))
+	for i, name := range names {
+		vars[i] = name + "=" + env[name]
+	}
+
+	return vars, nil
+}
+
+// Values gets all of the secret values a template's builds can see, so that
+// they can be redacted from build logs.
+//
+// The values in YAML and JSON files are used one by one, and other files are
+// used whole.
+func (c *Config) Values(template string) ([]string, error) {
+	var values []string
+	for _, r := range c.rules(template) {
+		for _, f := range r.Files {
+			v, err := fileValues(f.Source)
+			if err != nil {
+				if os.IsNotExist(errors.Cause(err)) {
+					continue
+				}
+
+				return nil, err
+			}
+			values = append(values, v...)
+		}
+	}
+
+	vars, err := c.Variables(template)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range vars {
+		values = append(values, v)
+	}
+
+	env, err := c.Env(template)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range env {
+		values = append(values, e[strings.Index(e, "=")+1:])
+	}
+
+	return values, nil
+}
+
+func (s Source) value() (string, error) {
+	if s.Env != "" {
+		v, ok := os.LookupEnv(s.Env)
+		if !ok {
+			return "", errors.Errorf("environment variable %s is not set", s.Env)
+		}
+		return v, nil
+	}
+
+	b, err := ioutil.ReadFile(s.File)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// fileValues reads the secret values in a file, or in every file in a
+// directory.
+func fileValues(p string) ([]string, error) {
+	var values []string
+	err := filepath.Walk(p, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		switch filepath.Ext(p) {
+		case ".yml", ".yaml", ".json":
+			v, err := parseValues(b)
+			if err != nil {
+				return errors.Wrapf(err, "could not parse secrets file %s", p)
+			}
+			values = appendStrings(values, v)
+		default:
+			values = append(values, string(b))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read secrets")
+	}
+
+	return values, nil
+}
+
+// parseValues parses a YAML or JSON secrets file, keeping numbers as they
+// were written so that large numbers don't lose any digits.
+func parseValues(b []byte) (interface{}, error) {
+	j, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.UseNumber()
+	if err = d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// appendStrings appends all of the strings and numbers in a parsed secrets
+// file, since numbers like PINs or numeric tokens can be secret too.
+//
+// Booleans and nulls are left out, since redacting every "true" from the log
+// would hide a lot without protecting anything.
+func appendStrings(values []string, v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return append(values, v)
+	case json.Number:
+		return append(values, v.String())
+	case map[string]interface{}:
+		for _, item := range v {
+			values = appendStrings(values, item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			values = appendStrings(values, item)
+		}
+	}
+
+	return values
+}
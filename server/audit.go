@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"github.com/travis-ci/imaged/auth"
+	"github.com/travis-ci/imaged/db"
+	pb "github.com/travis-ci/imaged/rpc/images"
+	"github.com/twitchtv/twirp"
+	"strconv"
+	"time"
+)
+
+// unknownPrincipal is recorded for calls that weren't authenticated, like when
+// authentication is disabled.
+const unknownPrincipal = "unknown"
+
+// auditTimeout is how long saving an audit event can take.
+const auditTimeout = 5 * time.Second
+
+// audit records a call to a method that changes something.
+//
+// It should be deferred with the method's returned error, so failed calls are
+// recorded too. A call isn't failed if the event can't be saved.
+//
+// The event is saved with a context of its own, since the change has already
+// happened by now even if the request's context was cancelled, like when the
+// client disconnected.
+func (s *Server) audit(ctx context.Context, method string, summary string, err error) {
+	principal := auth.Principal(ctx)
+	if principal == "" {
+		principal = unknownPrincipal
+	}
+
+	event := &db.AuditEvent{
+		Principal: principal,
+		Method:    method,
+		Summary:   summary,
+		Code:      errorCode(err),
+	}
+
+	saveCtx, cancel := context.WithTimeout(context.Background(), auditTimeout)
+	defer cancel()
+
+	if err := s.DB.CreateAuditEvent(saveCtx, event); err != nil {
+		log.WithFields(log.Fields{
+			"principal": event.Principal,
+			"method":    event.Method,
+			"summary":   event.Summary,
+			"code":      event.Code,
+		}).WithError(err).Error("could not save audit event")
+	}
+}
+
+// errorCode returns the Twirp error code that a method's error is sent as, or
+// "ok" if there was no error.
+func errorCode(err error) string {
+	if err == nil {
+		return "ok"
+	}
+
+	if twerr, ok := err.(twirp.Error); ok {
+		return string(twerr.Code())
+	}
+
+	return string(twirp.Internal)
+}
+
+// ListAuditEvents lists calls to methods that change something, newest first.
+//
+// The events can be filtered, and they're returned a page at a time.
+func (s *Server) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	f := db.AuditEventFilter{
+		Principal:     req.Principal,
+		Method:        req.Method,
+		Code:          req.Code,
+		CreatedAfter:  unixTime(req.CreatedAfter),
+		CreatedBefore: unixTime(req.CreatedBefore),
+	}
+
+	var err error
+	if f.Limit, f.BeforeID, err = pageParams(req.PageSize, req.PageToken); err != nil {
+		return nil, err
+	}
+
+	// Get one extra event to find out if there's another page
+	pageSize := f.Limit
+	f.Limit++
+
+	events, err := s.DB.ListAuditEvents(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListAuditEventsResponse{}
+	if len(events) > pageSize {
+		events = events[:pageSize]
+		resp.NextPageToken = strconv.FormatInt(events[pageSize-1].ID, 10)
+	}
+
+	for _, e := range events {
+		resp.Events = append(resp.Events, e.Message())
+	}
+
+	return resp, nil
+}
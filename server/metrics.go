@@ -0,0 +1,41 @@
+package server
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twitchtv/twirp"
+	"time"
+)
+
+var (
+	rpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "imaged_rpc_requests_total",
+		Help: "API requests, by method and HTTP status code.",
+	}, []string{"method", "code"})
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "imaged_rpc_request_duration_seconds",
+		Help:    "How long API requests took, by method and HTTP status code.",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"method", "code"})
+)
+
+type requestStartKey struct{}
+
+// RequestReceived is a Twirp hook that notes when a request was received, so
+// ResponseSent can measure how long it took.
+func RequestReceived(ctx context.Context) (context.Context, error) {
+	return context.WithValue(ctx, requestStartKey{}, time.Now()), nil
+}
+
+// ResponseSent is a Twirp hook that counts a finished request and records how
+// long it took.
+func ResponseSent(ctx context.Context) {
+	method, _ := twirp.MethodName(ctx)
+	code, _ := twirp.StatusCode(ctx)
+	rpcRequests.WithLabelValues(method, code).Inc()
+
+	if start, ok := ctx.Value(requestStartKey{}).(time.Time); ok {
+		rpcDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	log "github.com/sirupsen/logrus"
+	"github.com/travis-ci/imaged/auth"
+	pb "github.com/travis-ci/imaged/rpc/images"
+	"github.com/travis-ci/imaged/templates"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// PushPath is the URL path that PushHandler should be mounted at.
+const PushPath = "/github/push"
+
+// maxPushPayloadSize is the largest push payload that PushHandler will read.
+const maxPushPayloadSize = 5 * 1024 * 1024
+
+// zeroSHA is the SHA GitHub sends as the before SHA of a new branch.
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// pushPrincipal is saved as who started the builds for a push.
+const pushPrincipal = "github-push"
+
+// PushHandler receives GitHub push webhooks for the templates repo and starts
+// builds of the templates that the push changed.
+type PushHandler struct {
+	Server *Server
+	// Secret is the webhook secret configured in GitHub.
+	Secret string
+	// Branches are the branches that trigger builds when they're pushed to.
+	Branches []string
+}
+
+type pushEvent struct {
+	Ref        string `json:"ref"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+}
+
+// ServeHTTP handles a GitHub webhook request.
+//
+// The request must be signed with the webhook secret. Each template that was
+// changed by the push is built at the pushed commit, going through the same
+// validation as StartBuild. The first push to a new branch is compared to the
+// repo's default branch. Pushes that can't be compared, like a force-push
+// whose previous commit is gone, are ignored rather than failed, so that
+// GitHub doesn't keep redelivering them.
+func (h *PushHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxPushPayloadSize))
+	if err != nil {
+		http.Error(w, "could not read request body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.validSignature(req.Header.Get("X-Hub-Signature-256"), body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	switch req.Header.Get("X-GitHub-Event") {
+	case "ping":
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case "push":
+	default:
+		http.Error(w, "event ignored", http.StatusAccepted)
+		return
+	}
+
+	var event pushEvent
+	if err = json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "could not parse push event", http.StatusBadRequest)
+		return
+	}
+
+	l := log.WithFields(log.Fields{
+		"ref":    event.Ref,
+		"before": event.Before,
+		"after":  event.After,
+	})
+
+	if event.Deleted || !h.buildsBranch(event.Ref) {
+		l.Debug("ignored push")
+		http.Error(w, "push ignored", http.StatusAccepted)
+		return
+	}
+
+	// A new branch has no commit before the push, so it's compared to the
+	// branch it was most likely created from
+	before := event.Before
+	if before == zeroSHA {
+		before = event.Repository.DefaultBranch
+		l = l.WithField("compared_to", before)
+	}
+
+	if before == "" {
+		l.Warn("ignored push to new branch without a default branch to compare to")
+		http.Error(w, "push ignored", http.StatusAccepted)
+		return
+	}
+
+	if err = h.Server.Templates.Fetch(req.Context()); err != nil {
+		l.WithError(err).Error("could not fetch pushed commits")
+		http.Error(w, "could not fetch pushed commits", http.StatusInternalServerError)
+		return
+	}
+
+	names, err := h.Server.Templates.ChangedTemplates(before, event.After)
+	if err == templates.ErrRevisionNotFound {
+		l.WithError(err).Warn("ignored push that can't be compared")
+		http.Error(w, "push ignored: revision not found in templates repo", http.StatusAccepted)
+		return
+	}
+	if err != nil {
+		l.WithError(err).Error("could not find changed templates")
+		http.Error(w, "could not find changed templates", http.StatusInternalServerError)
+		return
+	}
+
+	var resp struct {
+		Builds []int64 `json:"builds"`
+	}
+	// The builds are started at the hash of the pushed commit, which was
+	// fetched above, so validating them doesn't fetch again
+	ctx := auth.WithPrincipal(req.Context(), pushPrincipal)
+	for _, name := range names {
+		b, err := h.Server.StartBuild(ctx, &pb.StartBuildRequest{
+			Name:     name,
+			Revision: event.After,
+		})
+		if err != nil {
+			l.WithField("template", name).WithError(err).Error("could not start build for push")
+			continue
+		}
+
+		l.WithFields(log.Fields{
+			"template": name,
+			"build_id": b.Build.Id,
+		}).Info("started build for push")
+		resp.Builds = append(resp.Builds, b.Build.Id)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *PushHandler) validSignature(header string, body []byte) bool {
+	if h.Secret == "" || !strings.HasPrefix(header, "sha256=") {
+		return false
+	}
+
+	sig, err := hex.DecodeString(strings.TrimPrefix(header, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.Secret))
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+func (h *PushHandler) buildsBranch(ref string) bool {
+	for _, b := range h.Branches {
+		if ref == "refs/heads/"+b {
+			return true
+		}
+	}
+
+	return false
+}
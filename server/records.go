@@ -0,0 +1,347 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	log "github.com/sirupsen/logrus"
+	"github.com/travis-ci/imaged/auth"
+	"github.com/travis-ci/imaged/db"
+	"github.com/travis-ci/imaged/storage"
+	"github.com/twitchtv/twirp"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// invalidFileNameMessage explains which file names can be attached to a
+// build. The name becomes part of the record's storage key, so it must not be
+// able to point at the files of other records.
+const invalidFileNameMessage = "must be a relative path without .. segments or backslashes"
+
+// These are the URL paths that RecordHandler should be mounted at.
+const (
+	RecordsPath = "/records/"
+	BuildsPath  = "/builds/"
+)
+
+// RecordScopes are the scopes needed for each HTTP method of RecordHandler.
+var RecordScopes = map[string]auth.Scope{
+	http.MethodGet:  auth.ScopeRead,
+	http.MethodHead: auth.ScopeRead,
+	http.MethodPut:  auth.ScopeBuild,
+}
+
+// RecordHandler streams build records over plain HTTP, so that large records
+// never have to fit in memory or in a single RPC message.
+//
+// It handles these requests:
+//
+//	GET /records/<record id>
+//	GET /builds/<build id>/records/<file name>
+//	PUT /builds/<build id>/records/<file name>
+//
+// Downloads support a single byte range in the Range header, and have a Digest
+// header with the record's SHA-256 checksum when it's known. Getting a record
+// by file name gets the most recent record with that name. Uploading attaches
+// a new record to the build, like AttachRecord, with the request body as its
+// contents.
+type RecordHandler struct {
+	Server *Server
+}
+
+// ServeHTTP handles a request to download or upload a record.
+func (h *RecordHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	id, buildID, fileName, ok := parseRecordPath(req.URL.Path)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		h.download(w, req, id, buildID, fileName)
+	case http.MethodPut:
+		if id != 0 {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		h.upload(w, req, buildID, fileName)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// parseRecordPath gets either a record ID or a build ID and file name from
+// the path of a request.
+func parseRecordPath(p string) (id int64, buildID int64, fileName string, ok bool) {
+	if strings.HasPrefix(p, RecordsPath) {
+		id, err := strconv.ParseInt(strings.TrimPrefix(p, RecordsPath), 10, 64)
+		if err != nil || id <= 0 {
+			return 0, 0, "", false
+		}
+
+		return id, 0, "", true
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(p, BuildsPath), "/", 3)
+	if len(parts) != 3 || parts[1] != "records" || parts[2] == "" {
+		return 0, 0, "", false
+	}
+
+	buildID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || buildID <= 0 {
+		return 0, 0, "", false
+	}
+
+	return 0, buildID, parts[2], true
+}
+
+func (h *RecordHandler) download(w http.ResponseWriter, req *http.Request, id int64, buildID int64, fileName string) {
+	ctx := req.Context()
+	r, err := h.Server.fetchRecord(ctx, id, buildID, fileName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "record does not exist", http.StatusNotFound)
+		} else {
+			writeHTTPError(w, err)
+		}
+		return
+	}
+
+	size, err := h.Server.Storage.Size(ctx, r.S3Key)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+
+	offset, length := int64(0), size
+	status := http.StatusOK
+	if header := req.Header.Get("Range"); header != "" {
+		o, l, ok, satisfiable := parseRange(header, size)
+		if !satisfiable {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+			http.Error(w, "requested range not satisfiable", http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+
+		// Ranges that can't be parsed are ignored, and the whole record is sent
+		if ok {
+			offset, length = o, l
+			status = http.StatusPartialContent
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, size))
+		}
+	}
+
+	contentType := "application/octet-stream"
+	if r.ContentType != nil {
+		contentType = *r.ContentType
+	}
+
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.Header().Set("Content-Type", contentType)
+	if digest := recordDigest(r); digest != "" {
+		w.Header().Set("Digest", digest)
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(r.FileName)))
+	w.Header().Set("Content-Length", strconv.FormatInt(length, 10))
+
+	if req.Method == http.MethodHead {
+		w.WriteHeader(status)
+		return
+	}
+
+	body, err := h.Server.Storage.Download(ctx, r.S3Key, offset, length)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	defer body.Close()
+
+	w.WriteHeader(status)
+	if _, err = io.Copy(w, body); err != nil {
+		log.WithField("record_id", r.ID).WithError(err).Error("could not send record")
+	}
+}
+
+// recordDigest returns the value of a Digest header for a record, so clients
+// can verify what they downloaded, or "" if the record has no checksum.
+//
+// The digest is of the whole record, even when only a range is sent.
+func recordDigest(r *db.Record) string {
+	if r.SHA256 == nil {
+		return ""
+	}
+
+	sum, err := hex.DecodeString(*r.SHA256)
+	if err != nil {
+		return ""
+	}
+
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum)
+}
+
+// parseRange parses a Range header with a single byte range, returning the
+// offset and length of the range.
+//
+// ok is false if the header can't be parsed or asks for more than one range,
+// in which case it should be ignored. satisfiable is false if the range is
+// entirely outside of the file.
+func parseRange(header string, size int64) (offset int64, length int64, ok bool, satisfiable bool) {
+	if !strings.HasPrefix(header, "bytes=") {
+		return 0, 0, false, true
+	}
+
+	spec := strings.TrimSpace(strings.TrimPrefix(header, "bytes="))
+	i := strings.Index(spec, "-")
+	if i == -1 || strings.Contains(spec, ",") {
+		return 0, 0, false, true
+	}
+
+	start, end := strings.TrimSpace(spec[:i]), strings.TrimSpace(spec[i+1:])
+	if start == "" {
+		// A suffix range, like bytes=-500 for the last 500 bytes
+		n, err := strconv.ParseInt(end, 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, false, true
+		}
+		if n == 0 {
+			return 0, 0, false, false
+		}
+		if n > size {
+			n = size
+		}
+
+		return size - n, n, true, true
+	}
+
+	first, err := strconv.ParseInt(start, 10, 64)
+	if err != nil || first < 0 {
+		return 0, 0, false, true
+	}
+	if first >= size {
+		return 0, 0, false, false
+	}
+
+	last := size - 1
+	if end != "" {
+		last, err = strconv.ParseInt(end, 10, 64)
+		if err != nil || last < first {
+			return 0, 0, false, true
+		}
+		if last >= size {
+			last = size - 1
+		}
+	}
+
+	return first, last - first + 1, true, true
+}
+
+func (h *RecordHandler) upload(w http.ResponseWriter, req *http.Request, buildID int64, fileName string) {
+	ctx := req.Context()
+
+	var err error
+	var meta *storage.Metadata
+	defer func() {
+		var size int64
+		if meta != nil {
+			size = meta.Size
+		}
+		h.Server.audit(ctx, "UploadRecord", fmt.Sprintf("id=%d file_name=%s size=%d", buildID, fileName, size), err)
+	}()
+
+	if !isRelativePath(fileName) {
+		err = twirp.InvalidArgumentError("file_name", invalidFileNameMessage)
+		writeHTTPError(w, err)
+		return
+	}
+
+	build, err := h.Server.DB.GetBuild(ctx, buildID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = twirp.NotFoundError("build does not exist")
+		}
+		writeHTTPError(w, err)
+		return
+	}
+
+	record, meta, err := h.Server.attachRecord(ctx, build, fileName, req.Body)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	m := jsonpb.Marshaler{OrigName: true}
+	if err := m.Marshal(w, record.Message()); err != nil {
+		log.WithField("record_id", record.ID).WithError(err).Error("could not send uploaded record")
+	}
+}
+
+// attachRecord uploads a file and saves it as a record of a build that isn't
+// from any attempt.
+//
+// A build can only have one attached record with each name, so attaching a
+// name that's already taken is an AlreadyExists error. The upload goes to a key
+// of its own, so the file of the existing record is left alone.
+func (s *Server) attachRecord(ctx context.Context, build *db.Build, fileName string, r io.Reader) (*db.Record, *storage.Metadata, error) {
+	exists, err := s.DB.AttachedRecordExists(ctx, build, fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if exists {
+		return nil, nil, twirp.NewError(twirp.AlreadyExists, "build already has a record named "+fileName)
+	}
+
+	key, err := build.AttachedRecordKey(fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta, err := storage.UploadWithMetadata(ctx, s.Storage, key, fileName, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record, err := s.DB.CreateRecord(ctx, build, nil, &db.Record{
+		FileName:    fileName,
+		S3Key:       key,
+		SHA256:      &meta.SHA256,
+		Size:        &meta.Size,
+		ContentType: &meta.ContentType,
+	})
+	if err != nil {
+		// The record wasn't saved, like when another upload of the same name
+		// won the race, so nothing refers to the file
+		if delErr := s.Storage.Delete(ctx, key); delErr != nil {
+			log.WithField("key", key).WithError(delErr).Error("could not delete unused record upload")
+		}
+
+		if err == db.ErrRecordExists {
+			err = twirp.NewError(twirp.AlreadyExists, "build already has a record named "+fileName)
+		}
+		return nil, nil, err
+	}
+
+	return record, meta, nil
+}
+
+// writeHTTPError responds to a plain HTTP request with an error, using the
+// status that Twirp would use for it.
+func writeHTTPError(w http.ResponseWriter, err error) {
+	if twerr, ok := err.(twirp.Error); ok {
+		http.Error(w, twerr.Msg(), twirp.ServerHTTPStatusFromErrorCode(twerr.Code()))
+		return
+	}
+
+	log.WithError(err).Error("record request failed")
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
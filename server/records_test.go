@@ -0,0 +1,74 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestParseRecordPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		id       int64
+		buildID  int64
+		fileName string
+		ok       bool
+	}{
+		{"/records/12", 12, 0, "", true},
+		{"/records/0", 0, 0, "", false},
+		{"/records/-1", 0, 0, "", false},
+		{"/records/abc", 0, 0, "", false},
+		{"/records/", 0, 0, "", false},
+		{"/records/12/extra", 0, 0, "", false},
+		{"/builds/3/records/build.log", 0, 3, "build.log", true},
+		{"/builds/3/records/logs/packer.txt", 0, 3, "logs/packer.txt", true},
+		{"/builds/3/records/", 0, 0, "", false},
+		{"/builds/3/artifacts/build.log", 0, 0, "", false},
+		{"/builds/x/records/build.log", 0, 0, "", false},
+		{"/builds/0/records/build.log", 0, 0, "", false},
+		{"/builds/3", 0, 0, "", false},
+	}
+
+	for _, tt := range tests {
+		id, buildID, fileName, ok := parseRecordPath(tt.path)
+		if id != tt.id || buildID != tt.buildID || fileName != tt.fileName || ok != tt.ok {
+			t.Errorf("parseRecordPath(%q) = %d, %d, %q, %v, want %d, %d, %q, %v",
+				tt.path, id, buildID, fileName, ok, tt.id, tt.buildID, tt.fileName, tt.ok)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	const size = 1000
+
+	tests := []struct {
+		header      string
+		offset      int64
+		length      int64
+		ok          bool
+		satisfiable bool
+	}{
+		{"", 0, 0, false, true},
+		{"bytes=0-499", 0, 500, true, true},
+		{"bytes=500-", 500, 500, true, true},
+		{"bytes=999-999", 999, 1, true, true},
+		{"bytes=900-2000", 900, 100, true, true},
+		{"bytes= 10 - 19 ", 10, 10, true, true},
+		{"bytes=-200", 800, 200, true, true},
+		{"bytes=-2000", 0, 1000, true, true},
+		{"bytes=-0", 0, 0, false, false},
+		{"bytes=1000-", 0, 0, false, false},
+		{"bytes=5-1", 0, 0, false, true},
+		{"bytes=0-1,5-6", 0, 0, false, true},
+		{"bytes=abc", 0, 0, false, true},
+		{"bytes=x-5", 0, 0, false, true},
+		{"bytes=0-y", 0, 0, false, true},
+		{"items=0-1", 0, 0, false, true},
+	}
+
+	for _, tt := range tests {
+		offset, length, ok, satisfiable := parseRange(tt.header, size)
+		if offset != tt.offset || length != tt.length || ok != tt.ok || satisfiable != tt.satisfiable {
+			t.Errorf("parseRange(%q, %d) = %d, %d, %v, %v, want %d, %d, %v, %v",
+				tt.header, size, offset, length, ok, satisfiable, tt.offset, tt.length, tt.ok, tt.satisfiable)
+		}
+	}
+}
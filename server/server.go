@@ -1,29 +1,73 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/travis-ci/imaged/auth"
 	"github.com/travis-ci/imaged/db"
 	pb "github.com/travis-ci/imaged/rpc/images"
+	"github.com/travis-ci/imaged/schedule"
 	"github.com/travis-ci/imaged/storage"
+	"github.com/travis-ci/imaged/templates"
 	"github.com/travis-ci/imaged/worker"
 	"github.com/twitchtv/twirp"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// maxLogTailSize is the most bytes of a build log that TailBuildLog returns at once.
+const maxLogTailSize = 64 * 1024
+
+// These are the page sizes used by ListBuilds.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// defaultTemplatesRevision is the revision templates are read from when a
+// request doesn't ask for one.
+const defaultTemplatesRevision = "master"
+
 // Server handles API requests for imaged.
 type Server struct {
-	DB      *db.Connection
-	Storage *storage.Storage
-	Worker  *worker.Worker
+	DB        *db.Connection
+	Storage   storage.Storage
+	Workers   *worker.Pool
+	Templates *templates.Repository
 }
 
-// ListBuilds provides a list of recent builds that imaged has run.
+// ListBuilds provides a list of builds that imaged has run, newest first.
+//
+// The builds can be filtered, and they're returned a page at a time.
 func (s *Server) ListBuilds(ctx context.Context, req *pb.ListBuildsRequest) (*pb.ListBuildsResponse, error) {
-	builds, err := s.DB.RecentBuilds(ctx)
+	f, err := buildFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get one extra build to find out if there's another page
+	pageSize := f.Limit
+	f.Limit++
+
+	builds, err := s.DB.ListBuilds(ctx, f)
 	if err != nil {
 		return nil, err
 	}
 
 	resp := &pb.ListBuildsResponse{}
+	if len(builds) > pageSize {
+		builds = builds[:pageSize]
+		resp.NextPageToken = strconv.FormatInt(builds[pageSize-1].ID, 10)
+	}
+
 	for _, b := range builds {
 		resp.Builds = append(resp.Builds, b.Message())
 	}
@@ -31,6 +75,79 @@ func (s *Server) ListBuilds(ctx context.Context, req *pb.ListBuildsRequest) (*pb
 	return resp, nil
 }
 
+// principal returns who made a request, or nil if it's not known.
+func principal(ctx context.Context) *string {
+	if p := auth.Principal(ctx); p != "" {
+		return &p
+	}
+
+	return nil
+}
+
+func buildFilter(req *pb.ListBuildsRequest) (db.BuildFilter, error) {
+	f := db.BuildFilter{
+		Name:           req.Name,
+		Revision:       req.Revision,
+		FullRevision:   req.FullRevision,
+		CreatedAfter:   unixTime(req.CreatedAfter),
+		CreatedBefore:  unixTime(req.CreatedBefore),
+		FinishedAfter:  unixTime(req.FinishedAfter),
+		FinishedBefore: unixTime(req.FinishedBefore),
+	}
+
+	var err error
+	if f.Limit, f.BeforeID, err = pageParams(req.PageSize, req.PageToken); err != nil {
+		return f, err
+	}
+
+	for _, st := range req.Statuses {
+		if _, ok := pb.Build_Status_name[int32(st)]; !ok {
+			return f, twirp.InvalidArgumentError("statuses", "contains an unknown status")
+		}
+		f.Statuses = append(f.Statuses, db.BuildStatusFromEnum(st))
+	}
+
+	return f, nil
+}
+
+// pageParams checks the page size and token of a list request, returning how
+// many items to list and the ID that the page starts before.
+//
+// Page tokens are the ID of the last item on the previous page.
+func pageParams(pageSize int32, pageToken string) (int, int64, error) {
+	limit := int(pageSize)
+	switch {
+	case limit < 0:
+		return 0, 0, twirp.InvalidArgumentError("page_size", "cannot be negative")
+	case limit == 0:
+		limit = defaultPageSize
+	case limit > maxPageSize:
+		limit = maxPageSize
+	}
+
+	var beforeID int64
+	if pageToken != "" {
+		id, err := strconv.ParseInt(pageToken, 10, 64)
+		if err != nil || id <= 0 {
+			return 0, 0, twirp.InvalidArgumentError("page_token", "is not a valid page token")
+		}
+		beforeID = id
+	}
+
+	return limit, beforeID, nil
+}
+
+// unixTime converts a Unix time from a request into a time, treating zero as
+// not set.
+func unixTime(t int64) *time.Time {
+	if t == 0 {
+		return nil
+	}
+
+	tm := time.Unix(t, 0)
+	return &tm
+}
+
 // GetBuild gets the details about a particular build.
 func (s *Server) GetBuild(ctx context.Context, req *pb.GetBuildRequest) (*pb.GetBuildResponse, error) {
 	build, err := s.DB.GetBuildFull(ctx, req.Id)
@@ -63,23 +180,488 @@ func (s *Server) GetLastBuild(ctx context.Context, req *pb.GetLastBuildRequest)
 	return resp, nil
 }
 
-// StartBuild creates a new build and begins running it.
-func (s *Server) StartBuild(ctx context.Context, req *pb.StartBuildRequest) (*pb.StartBuildResponse, error) {
-	build, err := s.DB.CreateBuild(ctx, req.Name, req.Revision)
+// StartBuild creates a new build and adds it to the build queue.
+//
+// The template is validated first, and the build is rejected if it's invalid.
+// The build will run once a worker is free to run it.
+func (s *Server) StartBuild(ctx context.Context, req *pb.StartBuildRequest) (resp *pb.StartBuildResponse, err error) {
+	defer func() { s.audit(ctx, "StartBuild", fmt.Sprintf("name=%s revision=%s", req.Name, req.Revision), err) }()
+
+	if err = validateVariables(req.Variables, req.VarFiles); err != nil {
+		return nil, err
+	}
+
+	if req.TimeoutSeconds < 0 {
+		return nil, twirp.InvalidArgumentError("timeout_seconds", "cannot be negative")
+	}
+
+	build := &db.Build{
+		Name:      req.Name,
+		Revision:  req.Revision,
+		Variables: req.Variables,
+		VarFiles:  req.VarFiles,
+		Principal: principal(ctx),
+	}
+	if req.TimeoutSeconds > 0 {
+		build.TimeoutSeconds = &req.TimeoutSeconds
+	}
+
+	// Check the template before creating the build, so a typo doesn't leave a
+	// failed build behind
+	if err = s.Workers.Validate(ctx, build); err != nil {
+		if ierr, ok := err.(*worker.InvalidBuildError); ok {
+			return nil, twirp.InvalidArgumentError(ierr.Argument, ierr.Reason)
+		}
+
+		return nil, err
+	}
+
+	build, err = s.DB.CreateBuild(ctx, build)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.DB.QueueBuild(ctx, build); err != nil {
+		return nil, err
+	}
+
+	s.Workers.Notify()
+
+	resp = &pb.StartBuildResponse{
+		Build: build.Message(),
+	}
+
+	return resp, nil
+}
+
+func validateVariables(vars map[string]string, varFiles []string) error {
+	for k := range vars {
+		if k == "" {
+			return twirp.InvalidArgumentError("variables", "cannot have an empty name")
+		}
+
+		if k == worker.RecordsPathVariable {
+			return twirp.InvalidArgumentError("variables", k+" is set by imaged")
+		}
+	}
+
+	for _, f := range varFiles {
+		if !isRelativePath(f) {
+			return twirp.InvalidArgumentError("var_files", "must be paths inside the templates repo")
+		}
+	}
+
+	return nil
+}
+
+// isRelativePath returns whether a slash-separated path is relative and has
+// no .. segments, so it can't point outside of the directory it's relative to.
+//
+// Backslashes aren't allowed either, since they're path separators on Windows
+// and some tools treat them as separators everywhere.
+func isRelativePath(p string) bool {
+	if p == "" || path.IsAbs(p) || path.Clean(p) == "." || strings.Contains(p, "\\") {
+		return false
+	}
+
+	for _, s := range strings.Split(p, "/") {
+		if s == ".." {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CancelBuild stops a build that is queued or running.
+//
+// A queued build is cancelled right away. A running build is interrupted, and
+// it's marked as cancelled once Packer has cleaned up and its records are
+// uploaded. A build running on another imaged instance is interrupted the next
+// time that instance records a heartbeat for it.
+func (s *Server) CancelBuild(ctx context.Context, req *pb.CancelBuildRequest) (resp *pb.CancelBuildResponse, err error) {
+	defer func() { s.audit(ctx, "CancelBuild", fmt.Sprintf("id=%d", req.Id), err) }()
+
+	build, err := s.DB.GetBuild(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled, err := s.DB.CancelQueuedBuild(ctx, build)
+	if err != nil {
+		return nil, err
+	}
+
+	// The build may have been claimed by a worker since it was queued. If it's
+	// running on another instance, that instance cancels it when it next
+	// checks in.
+	if !cancelled && !s.Workers.Cancel(build.ID) {
+		requested, err := s.DB.RequestCancel(ctx, build)
+		if err != nil {
+			return nil, err
+		}
+
+		if !requested {
+			return nil, twirp.NewError(twirp.FailedPrecondition, "build is not queued or running")
+		}
+	}
+
+	resp = &pb.CancelBuildResponse{
+		Build: build.Message(),
+	}
+
+	return resp, nil
+}
+
+// RetryBuild starts a new build that runs a finished build again.
+//
+// The new build uses the same template, revision and variables as the
+// original, and it checks out the same commit that the original resolved its
+// revision to, so that the build is reproducible.
+func (s *Server) RetryBuild(ctx context.Context, req *pb.RetryBuildRequest) (resp *pb.RetryBuildResponse, err error) {
+	defer func() { s.audit(ctx, "RetryBuild", fmt.Sprintf("id=%d", req.Id), err) }()
+
+	original, err := s.DB.GetBuild(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if original.FinishedAt == nil {
+		return nil, twirp.NewError(twirp.FailedPrecondition, "only finished builds can be retried")
+	}
+
+	if original.FullRevision == nil {
+		return nil, twirp.NewError(twirp.FailedPrecondition, "build finished before its revision was resolved, so it cannot be retried")
+	}
+
+	build, err := s.DB.CreateBuild(ctx, &db.Build{
+		Name:           original.Name,
+		Revision:       original.Revision,
+		FullRevision:   original.FullRevision,
+		Variables:      original.Variables,
+		VarFiles:       original.VarFiles,
+		TimeoutSeconds: original.TimeoutSeconds,
+		RetriedFrom:    &original.ID,
+		Principal:      principal(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.DB.QueueBuild(ctx, build); err != nil {
+		return nil, err
+	}
+
+	s.Workers.Notify()
+
+	resp = &pb.RetryBuildResponse{
+		Build: build.Message(),
+	}
+
+	return resp, nil
+}
+
+// SetBuildReleased marks whether a build's image was released, so that its
+// records are never deleted by the retention policy.
+func (s *Server) SetBuildReleased(ctx context.Context, req *pb.SetBuildReleasedRequest) (resp *pb.SetBuildReleasedResponse, err error) {
+	defer func() {
+		s.audit(ctx, "SetBuildReleased", fmt.Sprintf("id=%d released=%t", req.Id, req.Released), err)
+	}()
+
+	build, err := s.DB.GetBuild(ctx, req.Id)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, twirp.NotFoundError("build does not exist")
+		}
+
 		return nil, err
 	}
 
-	s.Worker.Send(worker.Job{Build: build})
+	if err = s.DB.SetBuildReleased(ctx, build, req.Released); err != nil {
+		return nil, err
+	}
 
-	resp := &pb.StartBuildResponse{
+	resp = &pb.SetBuildReleasedResponse{
 		Build: build.Message(),
 	}
 
 	return resp, nil
 }
 
-// DownloadRecord downloads the file contents of a build record from S3.
+// TailBuildLog reads the lines of a build log starting at a byte offset.
+//
+// While the build is running, the lines are read as Packer writes them, so
+// clients can poll with the next offset to follow the build. Once the build
+// has finished, the lines are read from the uploaded build log record. The
+// log of a build running on another imaged instance can only be read from
+// that instance, so an Unavailable error naming it is returned instead.
+func (s *Server) TailBuildLog(ctx context.Context, req *pb.TailBuildLogRequest) (*pb.TailBuildLogResponse, error) {
+	if req.Offset < 0 {
+		return nil, twirp.InvalidArgumentError("offset", "cannot be negative")
+	}
+
+	if b, ok := s.Workers.ReadLog(req.BuildId, req.Offset, maxLogTailSize); ok {
+		return tailResponse(req.Offset, completeLines(b), false), nil
+	}
+
+	build, err := s.DB.GetBuild(ctx, req.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	if build.FinishedAt == nil {
+		if build.ClaimedBy != nil && *build.ClaimedBy != s.Workers.InstanceID() {
+			return nil, twirp.NewError(twirp.Unavailable, "build is running on imaged instance "+*build.ClaimedBy).
+				WithMeta("claimed_by", *build.ClaimedBy)
+		}
+
+		// The build hasn't started logging yet
+		return tailResponse(req.Offset, nil, false), nil
+	}
+
+	r, err := s.DB.GetRecordNamed(ctx, build.ID, db.BuildLogFileName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return tailResponse(req.Offset, nil, true), nil
+		}
+
+		return nil, err
+	}
+
+	size, err := s.Storage.Size(ctx, r.S3Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Offset >= size {
+		return tailResponse(req.Offset, nil, true), nil
+	}
+
+	// Only download the part of the log that's needed
+	rc, err := s.Storage.Download(ctx, r.S3Key, req.Offset, maxLogTailSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Offset+int64(len(b)) < size {
+		return tailResponse(req.Offset, completeLines(b), false), nil
+	}
+
+	return tailResponse(req.Offset, b, true), nil
+}
+
+func tailResponse(offset int64, b []byte, complete bool) *pb.TailBuildLogResponse {
+	return &pb.TailBuildLogResponse{
+		Lines:      string(b),
+		NextOffset: offset + int64(len(b)),
+		Complete:   complete,
+	}
+}
+
+// completeLines drops a partial line from the end of a chunk of a log, so that
+// it can be read again once the rest of the line has been written.
+//
+// If the chunk doesn't contain a whole line, it's returned as is, so that a
+// very long line doesn't keep the log from being read.
+func completeLines(b []byte) []byte {
+	i := bytes.LastIndexByte(b, '\n')
+	if i == -1 {
+		if len(b) < maxLogTailSize {
+			return nil
+		}
+
+		return b
+	}
+
+	return b[:i+1]
+}
+
+// CreateSchedule creates a schedule that builds a template regularly.
+//
+// The template is validated the same way as in StartBuild, and each scheduled
+// build is started through StartBuild too.
+func (s *Server) CreateSchedule(ctx context.Context, req *pb.CreateScheduleRequest) (resp *pb.CreateScheduleResponse, err error) {
+	defer func() {
+		s.audit(ctx, "CreateSchedule", fmt.Sprintf("name=%s revision=%s cron_expression=%q", req.Name, req.Revision, req.CronExpression), err)
+	}()
+
+	expr, err := schedule.Parse(req.CronExpression)
+	if err != nil {
+		return nil, twirp.InvalidArgumentError("cron_expression", err.Error())
+	}
+
+	next := expr.Next(time.Now().UTC())
+	if next.IsZero() {
+		return nil, twirp.InvalidArgumentError("cron_expression", "never matches")
+	}
+
+	if err = s.Workers.Validate(ctx, &db.Build{Name: req.Name, Revision: req.Revision}); err != nil {
+		if ierr, ok := err.(*worker.InvalidBuildError); ok {
+			return nil, twirp.InvalidArgumentError(ierr.Argument, ierr.Reason)
+		}
+
+		return nil, err
+	}
+
+	sc, err := s.DB.CreateSchedule(ctx, &db.Schedule{
+		Name:           req.Name,
+		Revision:       req.Revision,
+		CronExpression: req.CronExpression,
+		NextRunAt:      next,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &pb.CreateScheduleResponse{
+		Schedule: sc.Message(),
+	}
+
+	return resp, nil
+}
+
+// ListSchedules lists all of the schedules.
+func (s *Server) ListSchedules(ctx context.Context, req *pb.ListSchedulesRequest) (*pb.ListSchedulesResponse, error) {
+	schedules, err := s.DB.ListSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListSchedulesResponse{}
+	for _, sc := range schedules {
+		resp.Schedules = append(resp.Schedules, sc.Message())
+	}
+
+	return resp, nil
+}
+
+// DeleteSchedule deletes a schedule so it doesn't start any more builds.
+func (s *Server) DeleteSchedule(ctx context.Context, req *pb.DeleteScheduleRequest) (resp *pb.DeleteScheduleResponse, err error) {
+	defer func() { s.audit(ctx, "DeleteSchedule", fmt.Sprintf("id=%d", req.Id), err) }()
+
+	if err = s.DB.DeleteSchedule(ctx, req.Id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, twirp.NotFoundError("schedule does not exist")
+		}
+
+		return nil, err
+	}
+
+	return &pb.DeleteScheduleResponse{}, nil
+}
+
+// ListTemplates lists the Packer templates in the templates repo at a revision,
+// along with their last builds.
+//
+// The templates are read from the commits that have already been fetched, which
+// happens in the background and when the repo is pushed to. Templates that
+// can't be parsed are listed separately with their errors.
+func (s *Server) ListTemplates(ctx context.Context, req *pb.ListTemplatesRequest) (*pb.ListTemplatesResponse, error) {
+	ts, invalid, h, err := s.Templates.List(templatesRevision(req.Revision))
+	if err != nil {
+		return nil, templatesError(err)
+	}
+
+	resp := &pb.ListTemplatesResponse{
+		FullRevision: h.String(),
+	}
+	for _, t := range ts {
+		msg, err := s.templateMessage(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		resp.Templates = append(resp.Templates, msg)
+	}
+
+	for _, e := range invalid {
+		resp.InvalidTemplates = append(resp.InvalidTemplates, &pb.InvalidTemplate{
+			Name:  e.Name,
+			Error: e.Err.Error(),
+		})
+	}
+
+	return resp, nil
+}
+
+// GetTemplate gets the details of a Packer template in the templates repo at a
+// revision, along with its last build.
+//
+// Like ListTemplates, the template is read from the commits that have already
+// been fetched.
+func (s *Server) GetTemplate(ctx context.Context, req *pb.GetTemplateRequest) (*pb.GetTemplateResponse, error) {
+	if req.Name == "" {
+		return nil, twirp.InvalidArgumentError("name", "cannot be empty")
+	}
+
+	t, h, err := s.Templates.Get(templatesRevision(req.Revision), req.Name)
+	if err != nil {
+		return nil, templatesError(err)
+	}
+
+	msg, err := s.templateMessage(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetTemplateResponse{
+		Template:     msg,
+		FullRevision: h.String(),
+	}
+
+	return resp, nil
+}
+
+func (s *Server) templateMessage(ctx context.Context, t *templates.Template) (*pb.Template, error) {
+	msg := t.Message()
+
+	build, err := s.DB.LastBuild(ctx, t.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if build != nil {
+		msg.LastBuild = build.Message()
+	}
+
+	return msg, nil
+}
+
+func templatesRevision(rev string) string {
+	if rev == "" {
+		return defaultTemplatesRevision
+	}
+
+	return rev
+}
+
+// templatesError converts errors from the templates repo into the right Twirp
+// errors.
+func templatesError(err error) error {
+	if pe, ok := errors.Cause(err).(*templates.ParseError); ok {
+		return twirp.NewError(twirp.FailedPrecondition, pe.Error())
+	}
+
+	switch errors.Cause(err) {
+	case templates.ErrRevisionNotFound, templates.ErrTemplateNotFound:
+		return twirp.NotFoundError(err.Error())
+	default:
+		return err
+	}
+}
+
+// DownloadRecord downloads the file contents of a build record from storage.
+//
+// The contents are checked against the record's checksum before they're
+// returned.
+//
+// The whole record is held in memory, so large records should be downloaded
+// through RecordHandler instead.
 func (s *Server) DownloadRecord(ctx context.Context, req *pb.DownloadRecordRequest) (*pb.DownloadRecordResponse, error) {
 	r, err := s.fetchRecord(ctx, req.Id, req.BuildId, req.FileName)
 	if err != nil {
@@ -91,6 +673,13 @@ func (s *Server) DownloadRecord(ctx context.Context, req *pb.DownloadRecordReque
 		return nil, err
 	}
 
+	if r.SHA256 != nil {
+		sum := sha256.Sum256(b)
+		if hex.EncodeToString(sum[:]) != *r.SHA256 {
+			return nil, twirp.NewError(twirp.DataLoss, "record contents do not match their checksum")
+		}
+	}
+
 	resp := &pb.DownloadRecordResponse{
 		Contents: b,
 	}
@@ -128,9 +717,17 @@ func (s *Server) fetchRecord(ctx context.Context, id int64, buildID int64, fileN
 // AttachRecord creates a new build record based on a file that was generated during the build.
 //
 // Generally, this won't need to be used, as imaged should upload records itself after a build runs. This API method is for backfilling records.
-func (s *Server) AttachRecord(ctx context.Context, req *pb.AttachRecordRequest) (*pb.AttachRecordResponse, error) {
-	if req.FileName == "" {
-		return nil, twirp.InvalidArgumentError("file_name", "cannot be empty")
+//
+// A build can only have one attached record with each file name, so attaching
+// the same name twice is an AlreadyExists error. The whole record is held in
+// memory, so large records should be uploaded through RecordHandler instead.
+func (s *Server) AttachRecord(ctx context.Context, req *pb.AttachRecordRequest) (resp *pb.AttachRecordResponse, err error) {
+	defer func() {
+		s.audit(ctx, "AttachRecord", fmt.Sprintf("id=%d file_name=%s size=%d", req.Id, req.FileName, len(req.Contents)), err)
+	}()
+
+	if !isRelativePath(req.FileName) {
+		return nil, twirp.InvalidArgumentError("file_name", invalidFileNameMessage)
 	}
 
 	build, err := s.DB.GetBuild(ctx, req.Id)
@@ -138,17 +735,12 @@ func (s *Server) AttachRecord(ctx context.Context, req *pb.AttachRecordRequest)
 		return nil, err
 	}
 
-	key := build.RecordKey(req.FileName)
-	if _, err = s.Storage.UploadBytes(ctx, key, req.Contents); err != nil {
-		return nil, err
-	}
-
-	record, err := s.DB.CreateRecord(ctx, build, req.FileName, key)
+	record, _, err := s.attachRecord(ctx, build, req.FileName, bytes.NewReader(req.Contents))
 	if err != nil {
 		return nil, err
 	}
 
-	resp := &pb.AttachRecordResponse{
+	resp = &pb.AttachRecordResponse{
 		Record: record.Message(),
 	}
 
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestIsRelativePath(t *testing.T) {
+	valid := []string{
+		"build.log",
+		"logs/packer.txt",
+		"./logs/packer.txt",
+		"logs//packer.txt",
+		"..log",
+		"logs/..packer",
+	}
+
+	invalid := []string{
+		"",
+		".",
+		"..",
+		"logs/..",
+		"logs/../build.log",
+		"/etc/passwd",
+		"../build.log",
+		"logs/../../build.log",
+		"../../../2/attempts/1/build.log",
+		"..\\2\\build.log",
+		"logs\\packer.txt",
+	}
+
+	for _, p := range valid {
+		if !isRelativePath(p) {
+			t.Errorf("isRelativePath(%q) = false, want true", p)
+		}
+	}
+
+	for _, p := range invalid {
+		if isRelativePath(p) {
+			t.Errorf("isRelativePath(%q) = true, want false", p)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalPathPrefix is the URL path that LocalStorage serves files from.
+const LocalPathPrefix = "/storage/"
+
+// publicURLExpiration is how long a public URL for a local file is valid,
+// matching the presigned URLs for S3.
+const publicURLExpiration = time.Hour
+
+// LocalStorage stores files in a directory on the local filesystem.
+//
+// It's also an http.Handler that serves the files for its public URLs, which
+// should be mounted at LocalPathPrefix.
+type LocalStorage struct {
+	Dir string
+
+	publicURL  string
+	signingKey []byte
+}
+
+// NewLocal creates a new storage object for a local directory.
+//
+// The public URLs are signed with the signing key, which should be the same
+// for every imaged instance serving the directory. If it's empty, a random key
+// is generated instead, so the public URLs only work on this instance and stop
+// working when imaged restarts.
+func NewLocal(dir string, publicURL string, signingKey string) (*LocalStorage, error) {
+	if dir == "" {
+		return nil, errors.New("a directory is required to create a local storage")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "could not create storage directory")
+	}
+
+	key := []byte(signingKey)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, errors.Wrap(err, "could not generate URL signing key")
+		}
+	}
+
+	return &LocalStorage{
+		Dir:        dir,
+		publicURL:  strings.TrimSuffix(publicURL, "/"),
+		signingKey: key,
+	}, nil
+}
+
+// Upload writes data from a reader to a file.
+//
+// The data is written to a temporary file first, so a file is never seen
+// partially written.
+//
+// Local files don't keep their content type, so it's ignored.
+func (s *LocalStorage) Upload(ctx context.Context, key string, r io.Reader, contentType string) (string, error) {
+	cr := &countingReader{r: r}
+	location, err := s.upload(key, cr)
+	observeUpload("local", cr, err)
+	return location, err
+}
+
+func (s *LocalStorage) upload(key string, r io.Reader) (string, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return "", errors.Wrap(err, "could not create directory for file")
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(p), ".upload-")
+	if err != nil {
+		return "", errors.Wrap(err, "could not create file")
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err = io.Copy(f, r); err != nil {
+		return "", errors.Wrap(err, "could not write file")
+	}
+
+	if err = f.Close(); err != nil {
+		return "", errors.Wrap(err, "could not write file")
+	}
+
+	if err = os.Rename(f.Name(), p); err != nil {
+		return "", errors.Wrap(err, "could not move file into place")
+	}
+
+	return (&url.URL{Scheme: "file", Path: p}).String(), nil
+}
+
+// Download opens a file for streaming, starting at a byte offset.
+func (s *LocalStorage) Download(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset > 0 {
+		if _, err = f.Seek(offset, io.SeekStart); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	if length < 0 {
+		return f, nil
+	}
+
+	return limitedReadCloser{io.LimitReader(f, length), f}, nil
+}
+
+// DownloadBytes reads a file into a byte array.
+func (s *LocalStorage) DownloadBytes(ctx context.Context, key string) ([]byte, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadFile(p)
+}
+
+// Size gets the size of a file.
+func (s *LocalStorage) Size(ctx context.Context, key string) (int64, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(p)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size(), nil
+}
+
+// Delete deletes a file.
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// PublicURL generates a signed URL for downloading a file from imaged.
+func (s *LocalStorage) PublicURL(ctx context.Context, key string) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(publicURLExpiration).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("signature", s.sign(key, expires))
+
+	u := s.publicURL + LocalPathPrefix + (&url.URL{Path: key}).EscapedPath()
+	return u + "?" + q.Encode(), nil
+}
+
+// ServeHTTP serves a file for a public URL, if its signature is valid and it
+// hasn't expired.
+func (s *LocalStorage) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := strings.TrimPrefix(req.URL.Path, LocalPathPrefix)
+	expires := req.URL.Query().Get("expires")
+	signature := req.URL.Query().Get("signature")
+
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp || !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		http.Error(w, "invalid or expired URL", http.StatusForbidden)
+		return
+	}
+
+	p, err := s.path(key)
+	if err != nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.NotFound(w, req)
+		} else {
+			http.Error(w, "could not open file", http.StatusInternalServerError)
+		}
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, "could not open file", http.StatusInternalServerError)
+		return
+	}
+
+	http.ServeContent(w, req, path.Base(key), info.ModTime(), f)
+}
+
+// path converts a key into the path of its file, making sure it can't point
+// outside of the storage directory.
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if key == "" || clean == "/" {
+		return "", errors.Errorf("invalid storage key %q", key)
+	}
+
+	return filepath.Join(s.Dir, filepath.FromSlash(clean)), nil
+}
+
+func (s *LocalStorage) sign(key string, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLocalStoragePath(t *testing.T) {
+	s := &LocalStorage{Dir: "/var/lib/imaged"}
+
+	tests := []struct {
+		key  string
+		path string
+	}{
+		{"records/1/build.log", "/var/lib/imaged/records/1/build.log"},
+		{"/records/1/build.log", "/var/lib/imaged/records/1/build.log"},
+		{"records//1/./build.log", "/var/lib/imaged/records/1/build.log"},
+		{"../../etc/passwd", "/var/lib/imaged/etc/passwd"},
+		{"records/../../../etc/passwd", "/var/lib/imaged/etc/passwd"},
+		{"", ""},
+		{"/", ""},
+		{".", ""},
+		{"..", ""},
+	}
+
+	for _, tt := range tests {
+		p, err := s.path(tt.key)
+		if tt.path == "" {
+			if err == nil {
+				t.Errorf("path(%q) = %q, want an error", tt.key, p)
+			}
+			continue
+		}
+
+		if err != nil || p != filepath.FromSlash(tt.path) {
+			t.Errorf("path(%q) = %q, %v, want %q", tt.key, p, err, tt.path)
+		}
+	}
+}
+
+func TestLocalStoragePublicURL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "imaged-storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := NewLocal(dir, "https://imaged.example.com/", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	const key = "records/1/packer build.log"
+	if _, err = s.Upload(ctx, key, bytes.NewBufferString("hello"), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+
+	publicURL, err := s.PublicURL(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(publicURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Host != "imaged.example.com" || u.Path != LocalPathPrefix+key {
+		t.Fatalf("PublicURL(%q) = %q, which is not for the right host and path", key, publicURL)
+	}
+
+	expires := u.Query().Get("expires")
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	signed := func(key string, expires string, signature string) string {
+		q := url.Values{}
+		q.Set("expires", expires)
+		q.Set("signature", signature)
+		return (&url.URL{Path: LocalPathPrefix + key, RawQuery: q.Encode()}).String()
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		code   int
+	}{
+		{"public URL", http.MethodGet, publicURL, http.StatusOK},
+		{"head", http.MethodHead, publicURL, http.StatusOK},
+		{"post", http.MethodPost, publicURL, http.StatusMethodNotAllowed},
+		{"no signature", http.MethodGet, (&url.URL{Path: LocalPathPrefix + key}).String(), http.StatusForbidden},
+		{"wrong signature", http.MethodGet, signed(key, expires, "abc123"), http.StatusForbidden},
+		{"other key", http.MethodGet, signed("records/2/build.log", expires, s.sign(key, expires)), http.StatusForbidden},
+		{"later expiry", http.MethodGet, signed(key, expires+"0", s.sign(key, expires)), http.StatusForbidden},
+		{"expired", http.MethodGet, signed(key, past, s.sign(key, past)), http.StatusForbidden},
+		{"invalid expiry", http.MethodGet, signed(key, "soon", s.sign(key, "soon")), http.StatusForbidden},
+		{"missing file", http.MethodGet, signed("records/2/build.log", expires, s.sign("records/2/build.log", expires)), http.StatusNotFound},
+	}
+
+	// Another instance with the same key accepts the URL, and one with a
+	// random key doesn't
+	other, err := NewLocal(dir, "https://imaged.example.com/", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	random, err := NewLocal(dir, "https://imaged.example.com/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(tt.method, tt.url, nil))
+
+		if w.Code != tt.code {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.code)
+		}
+
+		if tt.code == http.StatusOK && tt.method == http.MethodGet && w.Body.String() != "hello" {
+			t.Errorf("%s: body = %q, want %q", tt.name, w.Body.String(), "hello")
+		}
+	}
+
+	w := httptest.NewRecorder()
+	other.ServeHTTP(w, httptest.NewRequest(http.MethodGet, publicURL, nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status from instance with the same key = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	w = httptest.NewRecorder()
+	random.ServeHTTP(w, httptest.NewRequest(http.MethodGet, publicURL, nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status from instance with a random key = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
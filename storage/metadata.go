@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// sniffLen is how many bytes are read to detect a file's content type.
+const sniffLen = 512
+
+// Metadata describes a file that was uploaded, so that it can be checked when
+// it's downloaded again.
+type Metadata struct {
+	// SHA256 is the hex-encoded SHA-256 checksum of the file.
+	SHA256      string
+	Size        int64
+	ContentType string
+}
+
+// UploadWithMetadata uploads data from a reader while computing its checksum
+// and size.
+//
+// The content type is detected from the file's name and its first bytes, and
+// the file is stored with that content type.
+func UploadWithMetadata(ctx context.Context, s Storage, key string, name string, r io.Reader) (*Metadata, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "could not read file")
+	}
+
+	contentType := DetectContentType(name, head)
+	h := sha256.New()
+	cr := &countingReader{r: io.TeeReader(br, h)}
+	if _, err = s.Upload(ctx, key, cr, contentType); err != nil {
+		return nil, err
+	}
+
+	return &Metadata{
+		SHA256:      hex.EncodeToString(h.Sum(nil)),
+		Size:        cr.n,
+		ContentType: contentType,
+	}, nil
+}
+
+// DetectContentType guesses the MIME type of a file from its name and its
+// first bytes.
+//
+// The contents are sniffed first, but the file extension is used instead when
+// sniffing only finds generic text or binary data, like for JSON files.
+func DetectContentType(name string, head []byte) string {
+	sniffed := http.DetectContentType(head)
+	if sniffed != "application/octet-stream" && !strings.HasPrefix(sniffed, "text/plain") {
+		return sniffed
+	}
+
+	if byExt := mime.TypeByExtension(path.Ext(name)); byExt != "" {
+		return byExt
+	}
+
+	return sniffed
+}
@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	uploadBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "imaged_storage_upload_bytes_total",
+		Help: "Bytes uploaded to record storage, by storage backend.",
+	}, []string{"backend"})
+	uploadErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "imaged_storage_upload_errors_total",
+		Help: "Uploads to record storage that failed, by storage backend.",
+	}, []string{"backend"})
+)
+
+// observeUpload updates the upload metrics after an upload finishes.
+func observeUpload(backend string, r *countingReader, err error) {
+	if err != nil {
+		uploadErrors.WithLabelValues(backend).Inc()
+		return
+	}
+
+	uploadBytes.WithLabelValues(backend).Add(float64(r.n))
+}
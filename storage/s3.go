@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"time"
+)
+
+// S3Storage stores files in an S3 bucket.
+type S3Storage struct {
+	svc        *s3.S3
+	uploader   *s3manager.Uploader
+	downloader *s3manager.Downloader
+
+	Bucket string
+}
+
+// NewS3 creates a new storage object for a particular S3 bucket.
+//
+// The AWS credentials will be pulled from the environment.
+func NewS3(bucket string) (*S3Storage, error) {
+	if bucket == "" {
+		return nil, errors.New("a bucket is required to create a storage")
+	}
+
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Storage{
+		svc:        s3.New(sess),
+		uploader:   s3manager.NewUploader(sess),
+		downloader: s3manager.NewDownloader(sess),
+		Bucket:     bucket,
+	}, nil
+}
+
+// Upload uploads data from a reader to S3.
+func (s *S3Storage) Upload(ctx context.Context, key string, r io.Reader, contentType string) (string, error) {
+	cr := &countingReader{r: r}
+	input := &s3manager.UploadInput{
+		Bucket:      &s.Bucket,
+		Key:         &key,
+		Body:        cr,
+		ContentType: aws.String(contentType),
+	}
+	result, err := s.uploader.UploadWithContext(ctx, input)
+	observeUpload("s3", cr, err)
+	if err != nil {
+		return "", err
+	}
+	return result.Location, nil
+}
+
+// Download streams part of a file from S3, using a ranged request when only
+// part of the file is needed.
+func (s *S3Storage) Download(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if length == 0 {
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	input := &s3.GetObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+	}
+	if offset > 0 || length > 0 {
+		rng := "bytes=" + strconv.FormatInt(offset, 10) + "-"
+		if length > 0 {
+			rng += strconv.FormatInt(offset+length-1, 10)
+		}
+		input.Range = &rng
+	}
+
+	output, err := s.svc.GetObjectWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+// DownloadBytes downloads a byte array from S3.
+func (s *S3Storage) DownloadBytes(ctx context.Context, key string) ([]byte, error) {
+	buffer := aws.NewWriteAtBuffer(nil)
+	input := &s3.GetObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+	}
+	_, err := s.downloader.DownloadWithContext(ctx, buffer, input)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Size gets the size of a file stored in S3.
+func (s *S3Storage) Size(ctx context.Context, key string) (int64, error) {
+	input := &s3.HeadObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+	}
+	output, err := s.svc.HeadObjectWithContext(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+	return aws.Int64Value(output.ContentLength), nil
+}
+
+// Delete deletes a file from S3.
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	input := &s3.DeleteObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+	}
+	_, err := s.svc.DeleteObjectWithContext(ctx, input)
+	return err
+}
+
+// PublicURL generates a publicly-accessible URL for a file stored in S3.
+func (s *S3Storage) PublicURL(ctx context.Context, key string) (string, error) {
+	input := &s3.GetObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+	}
+	req, _ := s.svc.GetObjectRequest(input)
+	return req.Presign(time.Hour)
+}
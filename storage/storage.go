@@ -1,88 +1,77 @@
 package storage
 
 import (
-	"bytes"
 	"context"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/pkg/errors"
 	"io"
-	"time"
+	"net/url"
 )
 
 // Storage provides an interface for uploading and downloading files.
-type Storage struct {
-	svc        *s3.S3
-	uploader   *s3manager.Uploader
-	downloader *s3manager.Downloader
-
-	Bucket string
+type Storage interface {
+	// Upload uploads data from a reader with a content type, returning the
+	// location of the file.
+	Upload(ctx context.Context, key string, r io.Reader, contentType string) (string, error)
+	// Download opens a file for streaming, starting at a byte offset. At most
+	// length bytes are read, or the rest of the file if length is negative.
+	Download(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error)
+	// DownloadBytes downloads a file into a byte array.
+	DownloadBytes(ctx context.Context, key string) ([]byte, error)
+	// Size returns the size of a file in bytes.
+	Size(ctx context.Context, key string) (int64, error)
+	// Delete deletes a file. Deleting a file that doesn't exist is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+	// PublicURL generates a temporary URL that can be used to download a file
+	// without credentials.
+	PublicURL(ctx context.Context, key string) (string, error)
 }
 
-// New creates a new storage object for a particular S3 bucket.
+// New creates a new storage object based on the scheme of a URL.
+//
+// A URL like s3://bucket stores files in an S3 bucket, and a URL like
+// file:///var/lib/imaged stores them in a local directory. For compatibility,
+// a URL with no scheme is treated as the name of an S3 bucket.
 //
-// The AWS credentials will be pulled from the environment.
-func New(bucket string) (*Storage, error) {
-	if bucket == "" {
+// Public URLs for local storage are served by imaged itself, so publicURL must
+// be the base URL where clients can reach imaged, and they're signed with the
+// signingKey (see NewLocal). Both are ignored for S3.
+func New(rawurl string, publicURL string, signingKey string) (Storage, error) {
+	if rawurl == "" {
 		return nil, errors.New("a bucket is required to create a storage")
 	}
 
-	sess, err := session.NewSession()
+	u, err := url.Parse(rawurl)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "could not parse storage URL")
 	}
 
-	return &Storage{
-		svc:        s3.New(sess),
-		uploader:   s3manager.NewUploader(sess),
-		downloader: s3manager.NewDownloader(sess),
-		Bucket:     bucket,
-	}, nil
-}
-
-// Upload uploads data from a reader to S3.
-func (s *Storage) Upload(ctx context.Context, key string, r io.Reader) (string, error) {
-	input := &s3manager.UploadInput{
-		Bucket:      &s.Bucket,
-		Key:         &key,
-		Body:        r,
-		ContentType: aws.String("text/plain"),
-	}
-	result, err := s.uploader.UploadWithContext(ctx, input)
-	if err != nil {
-		return "", err
+	switch u.Scheme {
+	case "":
+		return NewS3(rawurl)
+	case "s3":
+		return NewS3(u.Host)
+	case "file":
+		return NewLocal(u.Path, publicURL, signingKey)
+	default:
+		return nil, errors.Errorf("unsupported storage scheme %q", u.Scheme)
 	}
-	return result.Location, nil
 }
 
-// UploadBytes uploads a byte array to S3.
-func (s *Storage) UploadBytes(ctx context.Context, key string, b []byte) (string, error) {
-	reader := bytes.NewReader(b)
-	return s.Upload(ctx, key, reader)
+// limitedReadCloser reads part of a file and closes the whole file.
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
 }
 
-// DownloadBytes downloads a byte array from S3.
-func (s *Storage) DownloadBytes(ctx context.Context, key string) ([]byte, error) {
-	buffer := aws.NewWriteAtBuffer(nil)
-	input := &s3.GetObjectInput{
-		Bucket: &s.Bucket,
-		Key:    &key,
-	}
-	_, err := s.downloader.DownloadWithContext(ctx, buffer, input)
-	if err != nil {
-		return nil, err
-	}
-	return buffer.Bytes(), nil
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
 }
 
-// PublicURL generates a publicly-accessible URL for a file stored in S3.
-func (s *Storage) PublicURL(ctx context.Context, key string) (string, error) {
-	input := &s3.GetObjectInput{
-		Bucket: &s.Bucket,
-		Key:    &key,
-	}
-	req, _ := s.svc.GetObjectRequest(input)
-	return req.Presign(time.Hour)
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
 }
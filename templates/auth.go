@@ -0,0 +1,170 @@
+package templates
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+	"path"
+	"strings"
+)
+
+// defaultHTTPUsername is sent along with a token when no username is
+// configured. GitHub and GitLab accept any username for token auth.
+const defaultHTTPUsername = "imaged"
+
+// Auth is how imaged authenticates to the remotes of the templates repo and
+// its submodules.
+//
+// The auth that's used depends on the URL of each remote, so a repo cloned
+// over SSH can have submodules cloned over HTTPS, and the other way around.
+// Remotes that use neither protocol are accessed without auth.
+//
+// The HTTPS username and password are only sent to the hosts they're meant
+// for, so that a submodule hosted somewhere else never sees them, and never
+// over plain HTTP.
+type Auth struct {
+	// SSHKeyFile is the path to a private key for SSH remotes. If it's not
+	// set, keys are taken from the SSH agent.
+	SSHKeyFile string
+	// SSHKeyPassphrase decrypts the SSH private key, if it's encrypted.
+	SSHKeyPassphrase string
+	// KnownHostsFile is the path to a known_hosts file that SSH host keys are
+	// checked against. If it's not set, the files in SSH_KNOWN_HOSTS are used,
+	// or else ~/.ssh/known_hosts and /etc/ssh/ssh_known_hosts.
+	KnownHostsFile string
+
+	// Username is the username for HTTPS remotes.
+	Username string
+	// Password is the password or access token for HTTPS remotes. HTTPS
+	// remotes are accessed without auth if it's not set.
+	Password string
+	// Hosts are the hosts that the username and password are sent to.
+	// Defaults to the host of the templates repo's URL.
+	Hosts []string
+}
+
+// withDefaultHosts returns the auth with its hosts defaulting to the host of
+// the templates repo's URL.
+func (a *Auth) withDefaultHosts(url string) (*Auth, error) {
+	if a == nil || len(a.Hosts) > 0 {
+		return a, nil
+	}
+
+	ep, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse templates remote URL %s", url)
+	}
+
+	withHosts := *a
+	withHosts.Hosts = []string{ep.Host}
+	return &withHosts, nil
+}
+
+// method returns the auth to use for a remote URL, or nil if the remote
+// should be accessed without auth.
+func (a *Auth) method(url string) (transport.AuthMethod, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	ep, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse templates remote URL %s", url)
+	}
+
+	switch ep.Protocol {
+	case "ssh":
+		return a.sshMethod(ep)
+	case "http", "https":
+		return a.httpMethod(ep)
+	default:
+		return nil, nil
+	}
+}
+
+func (a *Auth) sshMethod(ep *transport.Endpoint) (transport.AuthMethod, error) {
+	if a.SSHKeyFile == "" && a.KnownHostsFile == "" {
+		// go-git uses the SSH agent and the default known_hosts files already
+		return nil, nil
+	}
+
+	user := ep.User
+	if user == "" {
+		user = ssh.DefaultUsername
+	}
+
+	var files []string
+	if a.KnownHostsFile != "" {
+		files = append(files, a.KnownHostsFile)
+	}
+
+	callback, err := ssh.NewKnownHostsCallback(files...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read SSH known hosts")
+	}
+
+	if a.SSHKeyFile == "" {
+		agent, err := ssh.NewSSHAgentAuth(user)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not connect to SSH agent")
+		}
+
+		agent.HostKeyCallback = callback
+		return agent, nil
+	}
+
+	keys, err := ssh.NewPublicKeysFromFile(user, a.SSHKeyFile, a.SSHKeyPassphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read SSH private key")
+	}
+
+	keys.HostKeyCallback = callback
+	return keys, nil
+}
+
+func (a *Auth) httpMethod(ep *transport.Endpoint) (transport.AuthMethod, error) {
+	if a.Password == "" || !a.sendsCredentialsTo(ep.Host) {
+		return nil, nil
+	}
+
+	if ep.Protocol != "https" {
+		return nil, errors.Errorf("refusing to send templates credentials to %s over plain HTTP", ep.Host)
+	}
+
+	username := a.Username
+	if username == "" {
+		username = defaultHTTPUsername
+	}
+
+	return &http.BasicAuth{
+		Username: username,
+		Password: a.Password,
+	}, nil
+}
+
+func (a *Auth) sendsCredentialsTo(host string) bool {
+	for _, h := range a.Hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveURL resolves a submodule URL that's relative to the URL of the repo
+// that contains it, like "../playbooks.git". Other URLs are returned as is.
+func resolveURL(base string, url string) (string, error) {
+	if !strings.HasPrefix(url, "./") && !strings.HasPrefix(url, "../") {
+		return url, nil
+	}
+
+	ep, err := transport.NewEndpoint(base)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not parse templates remote URL %s", base)
+	}
+
+	ep.Path = path.Join(ep.Path, url)
+	return ep.String(), nil
+}
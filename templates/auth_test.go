@@ -0,0 +1,77 @@
+package templates
+
+import (
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	"reflect"
+	"testing"
+)
+
+func TestResolveURL(t *testing.T) {
+	tests := []struct {
+		base string
+		url  string
+		want string
+	}{
+		{"https://github.com/travis-ci/packer-templates.git", "https://github.com/travis-ci/playbooks.git", "https://github.com/travis-ci/playbooks.git"},
+		{"https://github.com/travis-ci/packer-templates.git", "git@github.com:travis-ci/playbooks.git", "git@github.com:travis-ci/playbooks.git"},
+		{"https://github.com/travis-ci/packer-templates.git", "../playbooks.git", "https://github.com/travis-ci/playbooks.git"},
+		{"https://github.com/travis-ci/packer-templates.git", "../../other/playbooks.git", "https://github.com/other/playbooks.git"},
+		{"https://github.com/travis-ci/packer-templates.git", "./playbooks.git", "https://github.com/travis-ci/packer-templates.git/playbooks.git"},
+		{"https://user@git.example.com:8443/templates.git", "../playbooks.git", "https://user@git.example.com:8443/playbooks.git"},
+		{"ssh://git@github.com/travis-ci/packer-templates.git", "../playbooks.git", "ssh://git@github.com/travis-ci/playbooks.git"},
+		{"git@github.com:travis-ci/packer-templates.git", "../playbooks.git", "ssh://git@github.com/travis-ci/playbooks.git"},
+		{"/srv/git/packer-templates.git", "../playbooks.git", "file:///srv/git/playbooks.git"},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveURL(tt.base, tt.url)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveURL(%q, %q) = %q, %v, want %q", tt.base, tt.url, got, err, tt.want)
+		}
+	}
+}
+
+func TestAuthHTTPMethod(t *testing.T) {
+	creds := &http.BasicAuth{Username: "deploy", Password: "token"}
+	defaultUser := &http.BasicAuth{Username: defaultHTTPUsername, Password: "token"}
+
+	tests := []struct {
+		name    string
+		auth    *Auth
+		url     string
+		want    interface{}
+		wantErr bool
+	}{
+		{"no auth", nil, "https://github.com/travis-ci/packer-templates.git", nil, false},
+		{"no password", &Auth{Username: "deploy"}, "https://github.com/travis-ci/packer-templates.git", nil, false},
+		{"templates host", &Auth{Username: "deploy", Password: "token"}, "https://github.com/travis-ci/packer-templates.git", creds, false},
+		{"default username", &Auth{Password: "token"}, "https://github.com/travis-ci/packer-templates.git", defaultUser, false},
+		{"host case", &Auth{Username: "deploy", Password: "token"}, "https://GitHub.com/travis-ci/playbooks.git", creds, false},
+		{"other host", &Auth{Username: "deploy", Password: "token"}, "https://gitlab.com/travis-ci/playbooks.git", nil, false},
+		{"configured host", &Auth{Username: "deploy", Password: "token", Hosts: []string{"gitlab.com"}}, "https://gitlab.com/travis-ci/playbooks.git", creds, false},
+		{"not configured host", &Auth{Username: "deploy", Password: "token", Hosts: []string{"gitlab.com"}}, "https://github.com/travis-ci/packer-templates.git", nil, false},
+		{"plain HTTP", &Auth{Username: "deploy", Password: "token"}, "http://github.com/travis-ci/packer-templates.git", nil, true},
+		{"plain HTTP to other host", &Auth{Username: "deploy", Password: "token"}, "http://gitlab.com/travis-ci/playbooks.git", nil, false},
+		{"local", &Auth{Username: "deploy", Password: "token"}, "/srv/git/playbooks.git", nil, false},
+	}
+
+	for _, tt := range tests {
+		a, err := tt.auth.withDefaultHosts("https://github.com/travis-ci/packer-templates.git")
+		if err != nil {
+			t.Errorf("%s: withDefaultHosts returned error: %v", tt.name, err)
+			continue
+		}
+
+		method, err := a.method(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: method(%q) returned error %v, want error: %v", tt.name, tt.url, err, tt.wantErr)
+			continue
+		}
+
+		if tt.want == nil && method != nil {
+			t.Errorf("%s: method(%q) = %v, want no auth", tt.name, tt.url, method)
+		} else if tt.want != nil && !reflect.DeepEqual(method, tt.want) {
+			t.Errorf("%s: method(%q) = %v, want %v", tt.name, tt.url, method, tt.want)
+		}
+	}
+}
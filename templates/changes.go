@@ -0,0 +1,243 @@
+package templates
+
+import (
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ChangedTemplates finds the templates affected by the changes between two
+// revisions, sorted by name.
+//
+// A template is affected if its YAML file was added or changed. It's also
+// affected if a file that one of its provisioners uses was changed, like an
+// Ansible playbook. The files a provisioner uses are read from its path
+// options, like playbook_file or scripts, relative to the root of the repo.
+// Playbooks and manifests pull in the roles and files next to them, so
+// changes anywhere in their directory count. Templates that were deleted are
+// left out, and templates that can't be parsed are only affected by changes
+// to their own YAML.
+func (r *Repository) ChangedTemplates(before string, after string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, beforeTree, err := r.tree(before)
+	if err != nil {
+		return nil, err
+	}
+
+	_, afterTree, err := r.tree(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(beforeTree, afterTree)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not diff templates revisions")
+	}
+
+	changed := make(map[string]bool)
+	var files []string
+	for _, c := range changes {
+		for _, name := range []string{c.From.Name, c.To.Name} {
+			if name == "" {
+				continue
+			}
+
+			if t, ok := templateName(name); ok {
+				changed[t] = true
+			} else {
+				files = append(files, name)
+			}
+		}
+	}
+
+	dir, err := afterTree.Tree(Dir)
+	if err != nil {
+		if err == object.ErrDirectoryNotFound {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "could not read templates directory")
+	}
+
+	var names []string
+	for i := range dir.Entries {
+		e := &dir.Entries[i]
+		name, ok := templateName(path.Join(Dir, e.Name))
+		if !ok || !e.Mode.IsFile() {
+			continue
+		}
+
+		if changed[name] {
+			names = append(names, name)
+			continue
+		}
+
+		if len(files) == 0 {
+			continue
+		}
+
+		f, err := dir.TreeEntryFile(e)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read template %s", name)
+		}
+
+		contents, err := f.Contents()
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read template %s", name)
+		}
+
+		paths, err := provisionerPaths([]byte(contents))
+		if err != nil {
+			// There's no telling which files a broken template uses
+			continue
+		}
+
+		if usesAny(paths, files) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// templateName returns the name of the template at a path in the repo, if
+// the path is a template's YAML file.
+func templateName(p string) (string, bool) {
+	if path.Dir(p) != Dir || path.Ext(p) != ".yml" {
+		return "", false
+	}
+
+	return strings.TrimSuffix(path.Base(p), ".yml"), true
+}
+
+// pathOptions are the provisioner options that name files or directories in
+// the templates repo.
+//
+// Options set to true name a file that pulls in other files next to it, like
+// an Ansible playbook and its roles, so the whole directory the file is in is
+// used.
+var pathOptions = map[string]bool{
+	"cookbook_paths":      false,
+	"data_bags_path":      false,
+	"environments_path":   false,
+	"galaxy_file":         false,
+	"group_vars":          false,
+	"hiera_config_path":   false,
+	"host_vars":           false,
+	"inventory_directory": false,
+	"inventory_file":      false,
+	"manifest_dir":        false,
+	"manifest_file":       true,
+	"module_paths":        false,
+	"playbook_dir":        false,
+	"playbook_file":       true,
+	"playbook_paths":      false,
+	"role_paths":          false,
+	"roles_path":          false,
+	"script":              false,
+	"scripts":             false,
+	"source":              false,
+	"sources":             false,
+}
+
+// provisionerPaths returns the paths in the repo that a template's
+// provisioners use, relative to the root of the repo.
+func provisionerPaths(yml []byte) ([]string, error) {
+	var src struct {
+		Provisioners []map[string]interface{} `json:"provisioners"`
+	}
+
+	if err := yaml.Unmarshal(yml, &src); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, p := range src.Provisioners {
+		for option, value := range p {
+			wholeDir, ok := pathOptions[option]
+			if !ok {
+				continue
+			}
+
+			for _, v := range stringValues(value) {
+				if rp := repoPath(v, wholeDir); rp != "" {
+					paths = append(paths, rp)
+				}
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+// stringValues returns an option's value if it's a string, or the strings in
+// it if it's a list.
+func stringValues(value interface{}) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var values []string
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
+
+// repoPath converts a path from a provisioner option into a clean path
+// relative to the root of the repo, where Packer runs. Returns "" for paths
+// outside of the repo.
+//
+// Only the part of the path before any template function is kept, since the
+// rest depends on the build, so the directory it's in is used instead. Paths
+// that start with a template function are left out.
+func repoPath(p string, wholeDir bool) string {
+	dynamic := false
+	if i := strings.Index(p, "{{"); i != -1 {
+		// Stand in for the rest of the path, so that the directory the
+		// function is in is used
+		p, dynamic = p[:i]+"_", true
+	}
+
+	clean := path.Clean(p)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return ""
+	}
+
+	if dynamic || wholeDir {
+		if dir := path.Dir(clean); dir != "." {
+			return dir
+		}
+
+		if dynamic {
+			return ""
+		}
+	}
+
+	return clean
+}
+
+// usesAny reports whether any of the files are one of the paths, or inside
+// one of them. The path "." is the whole repo.
+func usesAny(paths []string, files []string) bool {
+	for _, f := range files {
+		for _, p := range paths {
+			if p == "." || f == p || strings.HasPrefix(f, p+"/") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
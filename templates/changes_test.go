@@ -0,0 +1,176 @@
+package templates
+
+import (
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var changesBaseFiles = map[string]string{
+	"templates/linux.yml": `
+provisioners:
+- type: ansible
+  playbook_file: linux_playbooks/site.yml
+- type: shell
+  scripts:
+  - ./scripts/cleanup.sh
+`,
+	"templates/windows.yml": `
+provisioners:
+- type: powershell
+  script: "windows/{{user ` + "`variant`" + `}}.ps1"
+`,
+	"templates/docker.yml": `
+provisioners:
+- type: file
+  source: files/docker/daemon.json
+  destination: /etc/docker/daemon.json
+`,
+	"templates/broken.yml":                      "provisioners: [",
+	"linux_playbooks/site.yml":                  "- hosts: all",
+	"linux_playbooks/roles/ruby/tasks/main.yml": "- name: install ruby",
+	"scripts/cleanup.sh":                        "#!/bin/sh",
+	"scripts/other.sh":                          "#!/bin/sh",
+	"windows/base.ps1":                          "Write-Host base",
+	"files/docker/daemon.json":                  "{}",
+	"files/docker/other.json":                   "{}",
+	"README.md":                                 "# Templates",
+}
+
+func TestChangedTemplates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "imaged-changes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Repository{repo: repo}
+	base := commitFiles(t, repo, dir, plumbing.ZeroHash, changesBaseFiles, nil)
+
+	tests := []struct {
+		name    string
+		write   map[string]string
+		remove  []string
+		changed []string
+	}{
+		{"nothing", nil, nil, nil},
+		{"template", map[string]string{"templates/linux.yml": "builders: []"}, nil, []string{"linux"}},
+		{"new template", map[string]string{"templates/mac.yml": "builders: []"}, nil, []string{"mac"}},
+		{"broken template", map[string]string{"templates/broken.yml": "provisioners: [{"}, nil, []string{"broken"}},
+		{"playbook", map[string]string{"linux_playbooks/site.yml": "- hosts: linux"}, nil, []string{"linux"}},
+		{"playbook role", map[string]string{"linux_playbooks/roles/ruby/tasks/main.yml": "- name: ruby"}, nil, []string{"linux"}},
+		{"script", map[string]string{"scripts/cleanup.sh": "#!/bin/bash"}, nil, []string{"linux"}},
+		{"unused script", map[string]string{"scripts/other.sh": "#!/bin/bash"}, nil, nil},
+		{"file in directory of a template function", map[string]string{"windows/new.ps1": "Write-Host new"}, nil, []string{"windows"}},
+		{"file source", map[string]string{"files/docker/daemon.json": "{\"debug\": true}"}, nil, []string{"docker"}},
+		{"file next to file source", map[string]string{"files/docker/other.json": "[]"}, nil, nil},
+		{"top-level file", map[string]string{"README.md": "# Packer templates"}, nil, nil},
+		{"deleted template", map[string]string{"files/docker/daemon.json": "[]"}, []string{"templates/docker.yml"}, nil},
+		{"several", map[string]string{"scripts/cleanup.sh": "", "files/docker/daemon.json": ""}, []string{"windows/base.ps1"}, []string{"docker", "linux", "windows"}},
+	}
+
+	for _, tt := range tests {
+		after := base
+		if tt.write != nil || tt.remove != nil {
+			after = commitFiles(t, repo, dir, base, tt.write, tt.remove)
+		}
+
+		changed, err := r.ChangedTemplates(base.String(), after.String())
+		if err != nil {
+			t.Errorf("%s: ChangedTemplates returned error: %v", tt.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(changed, tt.changed) {
+			t.Errorf("%s: ChangedTemplates = %v, want %v", tt.name, changed, tt.changed)
+		}
+	}
+
+	if _, err = r.ChangedTemplates("0123456789abcdef0123456789abcdef01234567", base.String()); err != ErrRevisionNotFound {
+		t.Errorf("ChangedTemplates with unknown revision returned %v, want ErrRevisionNotFound", err)
+	}
+}
+
+func TestRepoPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		wholeDir bool
+		want     string
+	}{
+		{"scripts/setup.sh", false, "scripts/setup.sh"},
+		{"./scripts/setup.sh", false, "scripts/setup.sh"},
+		{"scripts//setup.sh", false, "scripts/setup.sh"},
+		{"playbooks/site.yml", true, "playbooks"},
+		{"site.yml", true, "site.yml"},
+		{"playbooks/", false, "playbooks"},
+		{".", false, "."},
+		{"windows/{{user `variant`}}.ps1", false, "windows"},
+		{"windows/{{user `variant`}}/setup.ps1", false, "windows"},
+		{"windows/base-{{user `variant`}}.ps1", false, "windows"},
+		{"{{user `scripts`}}/setup.sh", false, ""},
+		{"/etc/hosts", false, ""},
+		{"../secrets.yml", false, ""},
+		{"scripts/../../secrets.yml", false, ""},
+		{"..", false, ""},
+	}
+
+	for _, tt := range tests {
+		if got := repoPath(tt.path, tt.wholeDir); got != tt.want {
+			t.Errorf("repoPath(%q, %v) = %q, want %q", tt.path, tt.wholeDir, got, tt.want)
+		}
+	}
+}
+
+// commitFiles commits changes on top of a parent commit, or as the first
+// commit if the parent is the zero hash.
+func commitFiles(t *testing.T, repo *git.Repository, dir string, parent plumbing.Hash, write map[string]string, remove []string) plumbing.Hash {
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !parent.IsZero() {
+		if err = wt.Checkout(&git.CheckoutOptions{Hash: parent, Force: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, contents := range write {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range remove {
+		if _, err = wt.Remove(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h, err := wt.Commit("test", &git.CommitOptions{
+		Author: &object.Signature{Name: "imaged", Email: "imaged@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
@@ -0,0 +1,69 @@
+package templates
+
+import (
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MetadataFile is the path of the file in the templates repo that configures
+// how imaged builds the templates, relative to the root of the repo.
+//
+// It looks like this, with settings for each template by name:
+//
+//	templates:
+//	  travis-ci-macos:
+//	    timeout: 4h
+const MetadataFile = "imaged.yml"
+
+// Metadata is the contents of the metadata file.
+type Metadata struct {
+	Templates map[string]TemplateMetadata `json:"templates"`
+}
+
+// TemplateMetadata is the configuration for building a single template.
+type TemplateMetadata struct {
+	// Timeout is how long a build of the template can run, like "90m".
+	Timeout string `json:"timeout"`
+}
+
+// ReadMetadata reads the metadata file from a checkout of the templates repo.
+//
+// It's fine for the metadata file not to exist, so empty metadata is returned
+// in that case.
+func ReadMetadata(dir string) (*Metadata, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, MetadataFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Metadata{}, nil
+		}
+
+		return nil, errors.Wrap(err, "could not read templates metadata file")
+	}
+
+	var m Metadata
+	if err = yaml.Unmarshal(b, &m); err != nil {
+		return nil, errors.Wrap(err, "could not parse templates metadata file")
+	}
+
+	return &m, nil
+}
+
+// Timeout returns how long a build of a template can run, or zero if the
+// metadata doesn't set a timeout for it.
+func (m *Metadata) Timeout(name string) (time.Duration, error) {
+	t := m.Templates[name].Timeout
+	if t == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(t)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid timeout for template %s in metadata file", name)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,12 @@
+package templates
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var fetchDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+	Name:    "imaged_templates_fetch_duration_seconds",
+	Help:    "How long fetching the templates repo took.",
+	Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+})
@@ -0,0 +1,542 @@
+package templates
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/filemode"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dir is the directory in the templates repo that contains the Packer templates.
+const Dir = "templates"
+
+// fetchTimeout limits how long a fetch from the origin remote can take, so a
+// hung remote can't stall polling or builds forever.
+const fetchTimeout = 5 * time.Minute
+
+// These errors are returned when a revision or template can't be found in the
+// templates repo.
+var (
+	ErrRevisionNotFound = errors.New("revision not found in templates repo")
+	ErrTemplateNotFound = errors.New("template not found in templates repo")
+)
+
+// Repository is a local clone of the Git repo of Packer templates.
+//
+// It's safe to use from more than one goroutine, so templates can be read
+// from the repo while a build is using its worktree.
+type Repository struct {
+	mu   sync.Mutex
+	repo *git.Repository
+	path string
+	auth *Auth
+
+	// fetchMu makes fetches wait for each other. They don't hold mu, so the
+	// repo can be read while waiting on the remote.
+	fetchMu sync.Mutex
+}
+
+// Open opens the templates repo at a local path, cloning it from the URL if
+// it hasn't been cloned yet, and fetches the latest commits.
+//
+// The auth is used for every clone and fetch of the repo and its submodules.
+// It can be nil for repos that don't need any. Unless the auth says
+// otherwise, its HTTPS credentials are only sent to the host of the URL, or of
+// the origin remote of an existing clone if there's no URL.
+func Open(localPath string, url string, auth *Auth) (*Repository, error) {
+	if localPath == "" {
+		return nil, errors.New("a templates path is required to open the templates repo")
+	}
+
+	repo, err := git.PlainOpen(localPath)
+	if err != nil && err != git.ErrRepositoryNotExists {
+		return nil, errors.Wrap(err, "could not open existing templates repo")
+	}
+
+	if repo == nil && url == "" {
+		return nil, errors.New("a templates URL is required when templates are not already cloned")
+	}
+
+	if url == "" {
+		if url, err = originURL(repo); err != nil {
+			return nil, err
+		}
+	}
+
+	if auth, err = auth.withDefaultHosts(url); err != nil {
+		return nil, err
+	}
+
+	r := &Repository{path: localPath, auth: auth}
+	if repo == nil {
+		method, err := auth.method(url)
+		if err != nil {
+			return nil, err
+		}
+
+		repo, err = git.PlainClone(localPath, false, &git.CloneOptions{
+			URL:  url,
+			Auth: method,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not clone templates repo")
+		}
+
+		if err = updateSubmodules(context.Background(), repo, url, auth); err != nil {
+			return nil, err
+		}
+	}
+
+	r.repo = repo
+	if err = r.Fetch(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Fetch fetches the latest commits from the origin remote. The fetch is
+// stopped if the context is cancelled or it takes longer than fetchTimeout.
+//
+// The repo can still be read while the fetch is waiting on the remote. The
+// fetch uses its own handle on the repo, and the fetched commits are only
+// seen once it has finished.
+func (r *Repository) Fetch(ctx context.Context) error {
+	r.fetchMu.Lock()
+	defer r.fetchMu.Unlock()
+
+	start := time.Now()
+	defer func() {
+		fetchDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	repo, err := git.PlainOpen(r.path)
+	if err != nil {
+		return errors.Wrap(err, "could not open templates repo")
+	}
+
+	url, err := originURL(repo)
+	if err != nil {
+		return err
+	}
+
+	method, err := r.auth.method(url)
+	if err != nil {
+		return err
+	}
+
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: "origin",
+		Auth:       method,
+	})
+	if err != nil {
+		if err == git.NoErrAlreadyUpToDate {
+			return nil
+		}
+
+		return errors.Wrap(err, "could not fetch latest commits for templates repo")
+	}
+
+	// The old handle caches the packfiles it has seen, so readers switch to
+	// the fetching handle to see the new commits.
+	r.mu.Lock()
+	r.repo = repo
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Poll fetches the latest commits from the origin remote every interval, so
+// that templates can be read without waiting on the remote.
+//
+// It should be called in a goroutine.
+func (r *Repository) Poll(interval time.Duration) {
+	for {
+		time.Sleep(interval)
+
+		if err := r.Fetch(context.Background()); err != nil {
+			log.WithError(err).Error("could not fetch latest commits for templates repo")
+		}
+	}
+}
+
+// HasCommit reports whether a revision is the full hash of a commit that has
+// already been fetched. Such a revision always refers to the same commit, so
+// it can be read without fetching first.
+func (r *Repository) HasCommit(rev string) bool {
+	h := plumbing.NewHash(rev)
+	if h.IsZero() || h.String() != rev {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err := r.repo.CommitObject(h)
+	return err == nil
+}
+
+// Resolve finds the commit that a revision refers to.
+//
+// Branch names are resolved to the branch on the origin remote when there is
+// one, so that the latest fetched commit is used.
+func (r *Repository) Resolve(rev string) (plumbing.Hash, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.resolve(rev)
+}
+
+// Checkout checks out a commit in the worktree, discarding any local changes,
+// and updates the submodules to match it. Fetching the commits of submodules
+// is stopped if the context is cancelled.
+func (r *Repository) Checkout(ctx context.Context, h plumbing.Hash) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, err := r.repo.Worktree()
+	if err != nil {
+		return errors.Wrap(err, "could not get worktree for templates repo")
+	}
+
+	err = w.Checkout(&git.CheckoutOptions{
+		Hash:  h,
+		Force: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not checkout templates revision")
+	}
+
+	url, err := r.originURL()
+	if err != nil {
+		return err
+	}
+
+	return updateSubmodules(ctx, r.repo, url, r.auth)
+}
+
+// List reads all of the templates at a revision, sorted by name.
+//
+// The templates are read from the Git objects directly, so the worktree is
+// left alone. Templates that can't be parsed are left out, and their parse
+// errors are returned separately, so that one broken template doesn't hide
+// the others. The resolved revision is returned along with the templates.
+func (r *Repository) List(rev string) ([]*Template, []*ParseError, plumbing.Hash, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, tree, err := r.tree(rev)
+	if err != nil {
+		return nil, nil, h, err
+	}
+
+	dir, err := tree.Tree(Dir)
+	if err != nil {
+		if err == object.ErrDirectoryNotFound {
+			return nil, nil, h, nil
+		}
+
+		return nil, nil, h, errors.Wrap(err, "could not read templates directory")
+	}
+
+	var ts []*Template
+	var invalid []*ParseError
+	for i := range dir.Entries {
+		e := &dir.Entries[i]
+		if !e.Mode.IsFile() || path.Ext(e.Name) != ".yml" {
+			continue
+		}
+
+		f, err := dir.TreeEntryFile(e)
+		if err != nil {
+			return nil, nil, h, errors.Wrapf(err, "could not read template %s", e.Name)
+		}
+
+		t, err := readTemplate(strings.TrimSuffix(e.Name, ".yml"), f)
+		if parseErr, ok := err.(*ParseError); ok {
+			invalid = append(invalid, parseErr)
+			continue
+		}
+		if err != nil {
+			return nil, nil, h, err
+		}
+		ts = append(ts, t)
+	}
+
+	sort.Slice(ts, func(i, j int) bool {
+		return ts[i].Name < ts[j].Name
+	})
+	sort.Slice(invalid, func(i, j int) bool {
+		return invalid[i].Name < invalid[j].Name
+	})
+
+	return ts, invalid, h, nil
+}
+
+// Get reads a single template at a revision.
+//
+// Like List, the worktree is left alone, and the resolved revision is
+// returned along with the template.
+func (r *Repository) Get(rev string, name string) (*Template, plumbing.Hash, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, tree, err := r.tree(rev)
+	if err != nil {
+		return nil, h, err
+	}
+
+	if name == "" || strings.Contains(name, "/") {
+		return nil, h, ErrTemplateNotFound
+	}
+
+	f, err := tree.File(Path(name))
+	if err != nil {
+		if err == object.ErrFileNotFound {
+			return nil, h, ErrTemplateNotFound
+		}
+
+		return nil, h, errors.Wrapf(err, "could not read template %s", name)
+	}
+
+	t, err := readTemplate(name, f)
+	if err != nil {
+		return nil, h, err
+	}
+
+	return t, h, nil
+}
+
+// Export writes the files of a commit to a local directory, leaving the
+// worktree alone.
+//
+// The files of submodules are written too, fetching their commits if needed.
+func (r *Repository) Export(h plumbing.Hash, dir string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	url, err := r.originURL()
+	if err != nil {
+		return err
+	}
+
+	return exportCommit(r.repo, h, dir, url, r.auth)
+}
+
+// Path returns the path of a template's YAML file, relative to the root of
+// the templates repo.
+func Path(name string) string {
+	return path.Join(Dir, name+".yml")
+}
+
+func (r *Repository) originURL() (string, error) {
+	return originURL(r.repo)
+}
+
+func originURL(repo *git.Repository) (string, error) {
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return "", errors.Wrap(err, "could not find origin remote of templates repo")
+	}
+
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", errors.New("origin remote of templates repo has no URL")
+	}
+
+	return urls[0], nil
+}
+
+func (r *Repository) resolve(rev string) (plumbing.Hash, error) {
+	h, err := r.repo.ResolveRevision(plumbing.Revision("origin/" + rev))
+	if err == plumbing.ErrReferenceNotFound {
+		h, err = r.repo.ResolveRevision(plumbing.Revision(rev))
+	}
+
+	if err != nil {
+		if err == plumbing.ErrReferenceNotFound || err == plumbing.ErrObjectNotFound {
+			return plumbing.ZeroHash, ErrRevisionNotFound
+		}
+
+		return plumbing.ZeroHash, errors.Wrap(err, "could not resolve reference in templates repo")
+	}
+
+	return *h, nil
+}
+
+func (r *Repository) tree(rev string) (plumbing.Hash, *object.Tree, error) {
+	h, err := r.resolve(rev)
+	if err != nil {
+		return h, nil, err
+	}
+
+	commit, err := r.repo.CommitObject(h)
+	if err != nil {
+		return h, nil, errors.Wrap(err, "could not read templates commit")
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return h, nil, errors.Wrap(err, "could not read templates commit tree")
+	}
+
+	return h, tree, nil
+}
+
+func readTemplate(name string, f *object.File) (*Template, error) {
+	contents, err := f.Contents()
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read template %s", name)
+	}
+
+	return Parse(name, []byte(contents))
+}
+
+func exportCommit(repo *git.Repository, h plumbing.Hash, dir string, url string, auth *Auth) error {
+	commit, err := repo.CommitObject(h)
+	if err != nil {
+		return errors.Wrap(err, "could not read templates commit")
+	}
+
+	files, err := commit.Files()
+	if err != nil {
+		return errors.Wrap(err, "could not read templates commit files")
+	}
+
+	err = files.ForEach(func(f *object.File) error {
+		return exportFile(f, filepath.Join(dir, filepath.FromSlash(f.Name)))
+	})
+	if err != nil {
+		return err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return errors.Wrap(err, "could not read templates commit tree")
+	}
+
+	var modules *config.Modules
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		name, e, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "could not read templates commit tree")
+		}
+
+		if e.Mode != filemode.Submodule {
+			continue
+		}
+
+		if modules == nil {
+			if modules, err = readModules(tree); err != nil {
+				return err
+			}
+		}
+
+		if err = exportSubmodule(repo, modules, name, e.Hash, filepath.Join(dir, filepath.FromSlash(name)), url, auth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportSubmodule writes the files of a submodule's commit to a local
+// directory.
+//
+// The submodule is looked up in the .gitmodules file of the commit being
+// exported, rather than the worktree, so that revisions that add or move
+// submodules can be exported too.
+func exportSubmodule(repo *git.Repository, modules *config.Modules, name string, h plumbing.Hash, dir string, url string, auth *Auth) error {
+	var m *config.Submodule
+	for _, sub := range modules.Submodules {
+		if sub.Path == name {
+			m = sub
+			break
+		}
+	}
+	if m == nil {
+		return errors.Errorf("submodule %s is not in .gitmodules", name)
+	}
+
+	subURL, err := resolveURL(url, m.URL)
+	if err != nil {
+		return err
+	}
+
+	method, err := auth.method(subURL)
+	if err != nil {
+		return err
+	}
+
+	subRepo, err := openModule(repo, m.Name, subURL)
+	if err != nil {
+		return errors.Wrapf(err, "could not open submodule %s", name)
+	}
+
+	if _, err = subRepo.CommitObject(h); err == plumbing.ErrObjectNotFound {
+		err = subRepo.Fetch(&git.FetchOptions{Auth: method})
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			return errors.Wrapf(err, "could not fetch submodule %s", name)
+		}
+	}
+
+	return exportCommit(subRepo, h, dir, subURL, auth)
+}
+
+func exportFile(f *object.File, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0777); err != nil {
+		return errors.Wrapf(err, "could not create directory for %s", f.Name)
+	}
+
+	if f.Mode == filemode.Symlink {
+		target, err := f.Contents()
+		if err != nil {
+			return errors.Wrapf(err, "could not read symlink %s", f.Name)
+		}
+
+		return errors.Wrapf(os.Symlink(target, dest), "could not create symlink %s", f.Name)
+	}
+
+	mode, err := f.Mode.ToOSFileMode()
+	if err != nil {
+		return errors.Wrapf(err, "could not get file mode of %s", f.Name)
+	}
+
+	src, err := f.Reader()
+	if err != nil {
+		return errors.Wrapf(err, "could not read file %s", f.Name)
+	}
+	defer src.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return errors.Wrapf(err, "could not create file %s", f.Name)
+	}
+	defer out.Close()
+
+	if _, err = io.Copy(out, src); err != nil {
+		return errors.Wrapf(err, "could not write file %s", f.Name)
+	}
+
+	return nil
+}
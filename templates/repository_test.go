@@ -0,0 +1,71 @@
+package templates
+
+import (
+	"context"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "imaged-templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	originDir := filepath.Join(dir, "origin")
+	origin, err := git.PlainInit(originDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := commitFiles(t, origin, originDir, plumbing.ZeroHash, map[string]string{"templates/linux.yml": "builders: []"}, nil)
+
+	r, err := Open(filepath.Join(dir, "clone"), originDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h, err := r.Resolve("master"); h != first || err != nil {
+		t.Errorf("Resolve(master) after clone = %s, %v, want %s", h, err, first)
+	}
+
+	second := commitFiles(t, origin, originDir, plumbing.ZeroHash, map[string]string{"templates/mac.yml": "builders: []"}, nil)
+	if _, err = r.Resolve(second.String()); err != ErrRevisionNotFound {
+		t.Errorf("Resolve of a commit that wasn't fetched returned %v, want ErrRevisionNotFound", err)
+	}
+
+	if r.HasCommit(second.String()) {
+		t.Errorf("HasCommit of a commit that wasn't fetched = true, want false")
+	}
+
+	if err = r.Fetch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.HasCommit(second.String()) {
+		t.Errorf("HasCommit of a fetched commit = false, want true")
+	}
+
+	for _, rev := range []string{"master", first.String()[:7], plumbing.ZeroHash.String()} {
+		if r.HasCommit(rev) {
+			t.Errorf("HasCommit(%q) = true, want false", rev)
+		}
+	}
+
+	if h, err := r.Resolve("master"); h != second || err != nil {
+		t.Errorf("Resolve(master) after fetch = %s, %v, want %s", h, err, second)
+	}
+
+	if _, _, err = r.Get(second.String(), "mac"); err != nil {
+		t.Errorf("Get of a fetched template returned error: %v", err)
+	}
+
+	if err = r.Fetch(context.Background()); err != nil {
+		t.Errorf("Fetch with nothing new returned error: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package templates
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+)
+
+// submodule is a submodule of the templates repo, or of one of its
+// submodules, along with the auth for its remote.
+type submodule struct {
+	*git.Submodule
+	url  string
+	auth transport.AuthMethod
+}
+
+// openSubmodules initializes the submodules in a repo's worktree.
+//
+// Relative submodule URLs are resolved against the URL of the repo, since
+// go-git doesn't do that itself.
+func openSubmodules(repo *git.Repository, url string, auth *Auth) ([]*submodule, error) {
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get worktree for templates repo")
+	}
+
+	subs, err := w.Submodules()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read templates submodules")
+	}
+
+	var opened []*submodule
+	for _, s := range subs {
+		c := s.Config()
+		if c.URL, err = resolveURL(url, c.URL); err != nil {
+			return nil, err
+		}
+
+		method, err := auth.method(c.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = s.Init(); err != nil && err != git.ErrSubmoduleAlreadyInitialized {
+			return nil, errors.Wrapf(err, "could not initialize submodule %s", c.Path)
+		}
+
+		opened = append(opened, &submodule{Submodule: s, url: c.URL, auth: method})
+	}
+
+	return opened, nil
+}
+
+// updateSubmodules checks out the commits of a repo's submodules that its
+// worktree refers to, fetching them if needed, and then does the same for
+// their submodules.
+func updateSubmodules(ctx context.Context, repo *git.Repository, url string, auth *Auth) error {
+	subs, err := openSubmodules(repo, url, auth)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range subs {
+		if err = s.UpdateContext(ctx, &git.SubmoduleUpdateOptions{Auth: s.auth}); err != nil {
+			return errors.Wrapf(err, "could not update submodule %s", s.Config().Path)
+		}
+
+		sr, err := s.Repository()
+		if err != nil {
+			return errors.Wrapf(err, "could not open submodule %s", s.Config().Path)
+		}
+
+		if err = updateSubmodules(ctx, sr, s.url, auth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readModules reads the submodules from the .gitmodules file of a commit's
+// tree.
+func readModules(tree *object.Tree) (*config.Modules, error) {
+	modules := config.NewModules()
+
+	f, err := tree.File(".gitmodules")
+	if err != nil {
+		if err == object.ErrFileNotFound {
+			return modules, nil
+		}
+
+		return nil, errors.Wrap(err, "could not read .gitmodules")
+	}
+
+	contents, err := f.Contents()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read .gitmodules")
+	}
+
+	if err = modules.Unmarshal([]byte(contents)); err != nil {
+		return nil, errors.Wrap(err, "could not parse .gitmodules")
+	}
+
+	return modules, nil
+}
+
+// openModule opens the repo that Git keeps for a submodule inside the repo
+// that contains it, creating it if the submodule was never initialized.
+//
+// The repo is opened without a worktree, so that exporting from it leaves the
+// submodule's checkout alone.
+func openModule(repo *git.Repository, name string, url string) (*git.Repository, error) {
+	s, err := repo.Storer.Module(name)
+	if err != nil {
+		return nil, err
+	}
+
+	subRepo, err := git.Open(s, nil)
+	if err != git.ErrRepositoryNotExists {
+		return subRepo, err
+	}
+
+	if subRepo, err = git.Init(s, nil); err != nil {
+		return nil, err
+	}
+
+	_, err = subRepo.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{url},
+	})
+	return subRepo, err
+}
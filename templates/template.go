@@ -0,0 +1,105 @@
+package templates
+
+import (
+	"encoding/json"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	pb "github.com/travis-ci/imaged/rpc/images"
+)
+
+// Template is a Packer template, as it's written in YAML in the templates repo.
+type Template struct {
+	Name         string
+	Builders     []Builder
+	Variables    map[string]string
+	Provisioners []Provisioner
+}
+
+// Builder is one of the builders in a Packer template.
+type Builder struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Provisioner is one of the provisioners in a Packer template.
+type Provisioner struct {
+	Type string `json:"type"`
+}
+
+// ParseError is returned when a template's YAML source can't be parsed.
+type ParseError struct {
+	Name string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return "could not parse template " + e.Name + ": " + e.Err.Error()
+}
+
+// Parse reads a Packer template from its YAML source.
+func Parse(name string, yml []byte) (*Template, error) {
+	var src struct {
+		Builders     []Builder              `json:"builders"`
+		Variables    map[string]interface{} `json:"variables"`
+		Provisioners []Provisioner          `json:"provisioners"`
+	}
+
+	if err := yaml.Unmarshal(yml, &src); err != nil {
+		return nil, &ParseError{Name: name, Err: err}
+	}
+
+	t := &Template{
+		Name:         name,
+		Builders:     src.Builders,
+		Variables:    make(map[string]string, len(src.Variables)),
+		Provisioners: src.Provisioners,
+	}
+
+	// Packer names builders after their type when they aren't given a name
+	for i := range t.Builders {
+		if t.Builders[i].Name == "" {
+			t.Builders[i].Name = t.Builders[i].Type
+		}
+	}
+
+	// Variables without a default are required, so they are left empty
+	for k, v := range src.Variables {
+		switch v := v.(type) {
+		case nil:
+			t.Variables[k] = ""
+		case string:
+			t.Variables[k] = v
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not read variable %s of template %s", k, name)
+			}
+			t.Variables[k] = string(b)
+		}
+	}
+
+	return t, nil
+}
+
+// Message converts the template into a protobuf message.
+func (t *Template) Message() *pb.Template {
+	msg := &pb.Template{
+		Name:      t.Name,
+		Variables: t.Variables,
+	}
+
+	for _, b := range t.Builders {
+		msg.Builders = append(msg.Builders, &pb.Template_Builder{
+			Name: b.Name,
+			Type: b.Type,
+		})
+	}
+
+	for _, p := range t.Provisioners {
+		msg.Provisioners = append(msg.Provisioners, &pb.Template_Provisioner{
+			Type: p.Type,
+		})
+	}
+
+	return msg
+}
@@ -0,0 +1,266 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/travis-ci/imaged/db"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// These are the events that webhooks are sent for.
+const (
+	// EventBuildStarted is sent each time an attempt of a build starts.
+	EventBuildStarted = "build.started"
+	// EventBuildFinished is sent when a build finishes for good, after any retries.
+	EventBuildFinished = "build.finished"
+)
+
+// These are the headers sent with each webhook request.
+const (
+	// SignatureHeader contains the hex-encoded HMAC-SHA256 of the request body,
+	// keyed with the webhook secret and prefixed with "sha256=".
+	SignatureHeader = "X-Imaged-Signature"
+	EventHeader     = "X-Imaged-Event"
+	DeliveryHeader  = "X-Imaged-Delivery"
+)
+
+const (
+	defaultMaxAttempts  = 8
+	defaultPollInterval = 10 * time.Second
+	initialRetryDelay   = 30 * time.Second
+	maxRetryDelay       = time.Hour
+	requestTimeout      = 30 * time.Second
+)
+
+// Config contains options for configuring a new Dispatcher.
+type Config struct {
+	// URLs are the endpoints that every webhook is sent to.
+	URLs []string
+	// Secret is the key used to sign webhook payloads.
+	Secret string
+	// DB is the database connection used to keep track of deliveries.
+	DB *db.Connection
+	// MaxAttempts is how many times a delivery is attempted before it's marked as failed.
+	MaxAttempts int
+	// PollInterval is how often the dispatcher checks for deliveries that are due to be retried.
+	PollInterval time.Duration
+}
+
+// Dispatcher sends webhooks when builds change state.
+//
+// Webhooks are saved as deliveries in the database before they're sent, so
+// failed deliveries can be retried with backoff and inspected later.
+type Dispatcher struct {
+	config Config
+	client *http.Client
+	wakeup chan struct{}
+}
+
+// New creates a new dispatcher ready to send webhooks.
+func New(c Config) (*Dispatcher, error) {
+	if len(c.URLs) > 0 && c.Secret == "" {
+		return nil, errors.New("a secret is required to sign webhooks")
+	}
+
+	if c.MaxAttempts < 1 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+
+	if c.PollInterval == 0 {
+		c.PollInterval = defaultPollInterval
+	}
+
+	return &Dispatcher{
+		config: c,
+		client: &http.Client{Timeout: requestTimeout},
+		wakeup: make(chan struct{}, 1),
+	}, nil
+}
+
+// Notify queues a webhook about a build to be sent to each webhook URL.
+//
+// The payload includes the build's records, so it's loaded again from the
+// database. Errors are only logged, since webhooks shouldn't affect the build.
+func (d *Dispatcher) Notify(ctx context.Context, event string, b *db.Build) {
+	if len(d.config.URLs) == 0 {
+		return
+	}
+
+	l := log.WithFields(log.Fields{
+		"event":    event,
+		"build_id": b.ID,
+	})
+
+	payload, err := d.payload(ctx, event, b.ID)
+	if err != nil {
+		l.WithError(err).Error("could not create webhook payload")
+		return
+	}
+
+	for _, url := range d.config.URLs {
+		delivery, err := d.config.DB.CreateWebhookDelivery(ctx, &db.WebhookDelivery{
+			BuildID: b.ID,
+			Event:   event,
+			URL:     url,
+			Payload: payload,
+		})
+		if err != nil {
+			l.WithField("url", url).WithError(err).Error("could not queue webhook")
+			continue
+		}
+
+		l.WithField("delivery_id", delivery.ID).Debug("queued webhook")
+	}
+
+	select {
+	case d.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends webhooks as they're queued and retries failed deliveries when
+// they're due.
+//
+// It should be called in a goroutine.
+func (d *Dispatcher) Run() {
+	for {
+		delivery, err := d.config.DB.ClaimWebhookDelivery(context.Background())
+		if err != nil {
+			log.WithError(err).Error("could not claim a webhook delivery")
+		}
+
+		if delivery == nil {
+			select {
+			case <-d.wakeup:
+			case <-time.After(d.config.PollInterval):
+			}
+			continue
+		}
+
+		d.deliver(delivery)
+	}
+}
+
+func (d *Dispatcher) payload(ctx context.Context, event string, buildID int64) (string, error) {
+	build, err := d.config.DB.GetBuildFull(ctx, buildID)
+	if err != nil {
+		return "", errors.Wrap(err, "could not load build")
+	}
+
+	// Use the same JSON encoding of the build as the API
+	m := &jsonpb.Marshaler{OrigName: true}
+	buildJSON, err := m.MarshalToString(build.Message())
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode build")
+	}
+
+	b, err := json.Marshal(struct {
+		Event string          `json:"event"`
+		Build json.RawMessage `json:"build"`
+	}{event, json.RawMessage(buildJSON)})
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode webhook payload")
+	}
+
+	return string(b), nil
+}
+
+// deliver attempts to send a webhook and saves the result.
+func (d *Dispatcher) deliver(delivery *db.WebhookDelivery) {
+	l := log.WithFields(log.Fields{
+		"delivery_id": delivery.ID,
+		"event":       delivery.Event,
+		"build_id":    delivery.BuildID,
+		"url":         delivery.URL,
+	})
+
+	delivery.Attempts++
+	delivery.ResponseCode = nil
+	delivery.LastError = nil
+
+	var retryDelay time.Duration
+	code, err := d.send(delivery)
+	if code != 0 {
+		delivery.ResponseCode = &code
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = db.DeliveryStatusDelivered
+		l.Info("delivered webhook")
+	case delivery.Attempts >= d.config.MaxAttempts:
+		reason := err.Error()
+		delivery.LastError = &reason
+		delivery.Status = db.DeliveryStatusFailed
+		l.WithError(err).Error("failed to deliver webhook, giving up")
+	default:
+		reason := err.Error()
+		delivery.LastError = &reason
+		retryDelay = backoff(delivery.Attempts)
+		l.WithError(err).WithField("retry_in", retryDelay).Warn("failed to deliver webhook")
+	}
+
+	if err = d.config.DB.UpdateWebhookDelivery(context.Background(), delivery, retryDelay); err != nil {
+		l.WithError(err).Error("could not save webhook delivery")
+	}
+}
+
+// send posts the webhook payload, returning the response status code if the
+// request got a response.
+func (d *Dispatcher) send(delivery *db.WebhookDelivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, errors.Wrap(err, "could not create webhook request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, "sha256="+Sign(d.config.Secret, []byte(delivery.Payload)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not send webhook request")
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.Errorf("webhook endpoint responded with %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign computes the hex-encoded HMAC-SHA256 signature of a webhook payload.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns how long to wait before attempting a delivery again, which
+// doubles after each failed attempt.
+func backoff(attempts int) time.Duration {
+	delay := initialRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	return delay
+}
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/travis-ci/imaged/db"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBackoff(t *testing.T) {
+	if d := backoff(1); d != initialRetryDelay {
+		t.Errorf("backoff(1) = %v, want %v", d, initialRetryDelay)
+	}
+
+	prev := backoff(1)
+	for attempts := 2; attempts <= 20; attempts++ {
+		d := backoff(attempts)
+		if want := 2 * prev; want < maxRetryDelay && d != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, d, want)
+		}
+		if d > maxRetryDelay {
+			t.Errorf("backoff(%d) = %v, which is longer than %v", attempts, d, maxRetryDelay)
+		}
+		prev = d
+	}
+
+	if d := backoff(1000); d != maxRetryDelay {
+		t.Errorf("backoff(1000) = %v, want %v", d, maxRetryDelay)
+	}
+}
+
+func TestSend(t *testing.T) {
+	const payload = `{"build":{"id":"1"}}`
+	code := http.StatusNoContent
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		if string(body) != payload {
+			t.Errorf("request body = %q, want %q", body, payload)
+		}
+
+		// check the signature the way a receiver would
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if sig := req.Header.Get(SignatureHeader); !hmac.Equal([]byte(sig), []byte(expected)) {
+			t.Errorf("%s = %q, want %q", SignatureHeader, sig, expected)
+		}
+
+		if e := req.Header.Get(EventHeader); e != EventBuildFinished {
+			t.Errorf("%s = %q, want %q", EventHeader, e, EventBuildFinished)
+		}
+		if id := req.Header.Get(DeliveryHeader); id != "42" {
+			t.Errorf("%s = %q, want %q", DeliveryHeader, id, "42")
+		}
+
+		w.WriteHeader(code)
+	}))
+	defer ts.Close()
+
+	d, err := New(Config{URLs: []string{ts.URL}, Secret: "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delivery := &db.WebhookDelivery{ID: 42, Event: EventBuildFinished, URL: ts.URL, Payload: payload}
+	if c, err := d.send(delivery); c != http.StatusNoContent || err != nil {
+		t.Errorf("send() = %d, %v, want %d, nil", c, err, http.StatusNoContent)
+	}
+
+	code = http.StatusBadGateway
+	if c, err := d.send(delivery); c != http.StatusBadGateway || err == nil {
+		t.Errorf("send() to a failing endpoint = %d, %v, want %d and an error", c, err, http.StatusBadGateway)
+	}
+
+	ts.Close()
+	if c, err := d.send(delivery); c != 0 || err == nil {
+		t.Errorf("send() to a closed endpoint = %d, %v, want 0 and an error", c, err)
+	}
+}
@@ -0,0 +1,21 @@
+package worker
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// readLog reads up to max bytes of a build log file starting at offset.
+//
+// Only what has already been written to the file is read, so a read past the
+// end returns nothing rather than an error.
+func readLog(path string, offset int64, max int) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ioutil.ReadAll(io.NewSectionReader(f, offset, int64(max)))
+}
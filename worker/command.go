@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// interruptGracePeriod is how long a command gets to exit after it has been
+// interrupted before it's killed.
+const interruptGracePeriod = 5 * time.Minute
+
+// runCommand starts a command and waits for it to finish.
+//
+// Unlike exec.CommandContext, the command is interrupted rather than killed
+// when the context is done, which gives Packer a chance to destroy any VMs it
+// created. If the command doesn't exit within the grace period, it's killed.
+func runCommand(ctx context.Context, cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	cmd.Process.Signal(os.Interrupt)
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(interruptGracePeriod):
+	}
+
+	cmd.Process.Kill()
+	return <-done
+}
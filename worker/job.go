@@ -1,54 +1,112 @@
 package worker
 
 import (
-	"bufio"
 	"context"
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/travis-ci/imaged/db"
+	"github.com/travis-ci/imaged/secrets"
 	"github.com/travis-ci/imaged/storage"
-	"gopkg.in/src-d/go-git.v4"
-	"gopkg.in/src-d/go-git.v4/plumbing"
+	"github.com/travis-ci/imaged/templates"
+	"github.com/travis-ci/imaged/webhook"
 	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
 )
 
+// RecordsPathVariable is the Packer user variable that tells a template where
+// to save files that should be uploaded as build records.
+const RecordsPathVariable = "records_path"
+
 // Job describes a Packer build that the worker needs to run.
 type Job struct {
 	Build *db.Build
 
 	worker    *Worker
+	attempt   *db.Attempt
+	started   time.Time
 	log       *os.File
 	outputDir string
 }
 
 // Execute runs a single job.
-func (j *Job) Execute(ctx context.Context) error {
+//
+// If the context is cancelled, Packer is interrupted so that it can clean up
+// after itself, and the build is marked as cancelled. The same happens when the
+// build runs past its timeout, but the build is marked as timed out instead.
+// Whatever build log and records the build produced are uploaded no matter
+// how the build ends.
+//
+// Each run of the job is saved as an attempt of the build. If the attempt fails
+// and the build has attempts left, the build is queued again instead of being
+// finished.
+func (j *Job) Execute(ctx context.Context) (err error) {
 	// Avoid the global logger so that we can direct these messages to the build log
 	// If we used the global logger, then request logs would also go in the build log
 	// if they happened during a job.
 	log := logrus.New()
 	log.SetLevel(logrus.GetLevel())
 
-	j.db().StartBuild(ctx, j.Build)
-
 	l := log.WithFields(logrus.Fields{
+		"worker":   j.worker.id,
 		"build_id": j.Build.ID,
 		"name":     j.Build.Name,
 		"revision": j.Build.Revision,
 	})
 	l.Info("started build")
 
+	// The context gets a deadline once the build's timeout is known
+	var timeout time.Duration
+	stopTimeout := func() {}
+
 	// Assume the build fails unless we get to the end and mark it successful
 	j.Build.Status = db.BuildStatusFailed
-	defer j.db().FinishBuild(ctx, j.Build)
+	defer func() {
+		ctxErr := ctx.Err()
+		stopTimeout()
+
+		if j.Build.Status != db.BuildStatusSucceeded && ctxErr == context.Canceled {
+			j.Build.Status = db.BuildStatusCancelled
+			j.Build.FailureReason = nil
+		} else if j.Build.Status != db.BuildStatusSucceeded && ctxErr == context.DeadlineExceeded {
+			j.Build.Status = db.BuildStatusTimedOut
+			reason := "build timed out after " + timeout.String()
+			j.Build.FailureReason = &reason
+		} else if err != nil {
+			reason := err.Error()
+			j.Build.FailureReason = &reason
+		}
+
+		// Use a fresh context, since the build needs to finish even if it was cancelled
+		j.finish(context.Background(), l)
+	}()
+
+	j.attempt, err = j.db().CreateAttempt(ctx, j.Build)
+	if err != nil {
+		return errors.Wrap(err, "could not create build attempt")
+	}
+	l = l.WithField("attempt", j.attempt.Number)
+	l.Info("started build attempt")
+	j.started = time.Now()
+	if j.attempt.Number == 1 {
+		buildsStarted.WithLabelValues(j.Build.Name).Inc()
+	}
+	j.webhooks().Notify(ctx, webhook.EventBuildStarted, j.Build)
+
+	// Learn the secrets before anything is logged, so they never reach the log
+	secretValues, err := j.worker.secretValues(j.Build.Name)
+	if err != nil {
+		return err
+	}
 
 	// Prepare the output directory and build log
-	dir, err := ioutil.TempDir("", "imaged-build")
+	dir, err := ioutil.TempDir("", outputDirPrefix(j.Build))
 	if err != nil {
 		return errors.Wrap(err, "could not create build output directory")
 	}
@@ -56,20 +114,60 @@ func (j *Job) Execute(ctx context.Context) error {
 	defer os.RemoveAll(dir)
 	l.WithField("out_dir", dir).Debug("created build output directory")
 
-	logFile, err := os.Create(j.outputFile("build.log"))
+	logFile, err := os.Create(j.outputFile(db.BuildLogFileName))
 	if err != nil {
 		return errors.Wrap(err, "could not create build log file")
 	}
 	j.log = logFile
 	defer logFile.Close()
+	j.worker.setLog(j.Build.ID, logFile.Name())
 	l.Debug("created build log")
 
-	logWriter := bufio.NewWriter(logFile)
-	defer logWriter.Flush()
+	// Secrets are redacted before they reach the log. Whole lines are written
+	// to the file as soon as they're complete, so the log can be read from
+	// disk while the build is still running.
+	logWriter := newRedactingWriter(logFile, secretValues)
+	flushLog := func() {
+		logWriter.Flush()
+	}
 
 	// Write logger output to both stdout and the build log file
 	log.Out = io.MultiWriter(os.Stdout, logWriter)
 
+	recordsDir := filepath.Join(dir, "records")
+	if err = os.Mkdir(recordsDir, 0777); err != nil {
+		return err
+	}
+	l.WithField("records_path", recordsDir).Debug("created custom records directory")
+
+	defer func() {
+		flushLog()
+		logFile.Sync()
+
+		// Use a fresh context, since the records should be kept even if the build was cancelled
+		if err := j.createRecords(context.Background(), l, recordsDir); err != nil {
+			l.WithError(err).Error("failed to upload records")
+		}
+	}()
+
+	// The timeout counts from the start of the attempt and covers fetching
+	// and checking out the templates, so a hung remote can't block the worker
+	// forever. The template's own timeout can only be read once it's checked
+	// out, so it replaces this one afterwards.
+	parent := ctx
+	setTimeout := func(t time.Duration) {
+		stopTimeout()
+		timeout = t
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(parent, j.started.Add(timeout))
+		stopTimeout = cancel
+		l.WithField("timeout", timeout).Info("set build timeout")
+	}
+
+	if t := j.buildTimeout(); t > 0 {
+		setTimeout(t)
+	}
+
 	// Put the templates repository in a clean state at the right revision
 	rev, err := j.resetRepository(ctx)
 	if err != nil {
@@ -80,19 +178,39 @@ func (j *Job) Execute(ctx context.Context) error {
 	j.Build.FullRevision = &rev
 	j.db().UpdateBuild(ctx, j.Build)
 
-	// Install secrets where Ansible can pick them up
-	if err := j.installSecrets(ctx); err != nil {
+	t, err := j.templateTimeout()
+	if err != nil {
 		return err
 	}
-	l.Debug("installed secrets file")
+	if t > 0 {
+		setTimeout(t)
+	}
+
+	// Install the template's secrets for this build only
+	installed, err := j.secrets().Install(j.Build.Name, j.templatesDir())
+	defer j.cleanupSecrets(l, installed)
+	if err != nil {
+		return errors.Wrap(err, "could not install secrets")
+	}
 
-	cmd := exec.CommandContext(ctx, j.packer(), "version")
+	secretVarFile, err := writeSecretVarFile(j.secrets(), j.Build.Name, dir)
+	if err != nil {
+		return err
+	}
+
+	secretEnv, err := j.secrets().Env(j.Build.Name)
+	if err != nil {
+		return err
+	}
+	l.Debug("installed secrets")
+
+	cmd := exec.Command(j.packer(), "version")
 	cmd.Stdout = logWriter
 	cmd.Stderr = logWriter
-	if err = cmd.Run(); err != nil {
+	if err = runCommand(ctx, cmd); err != nil {
 		return errors.Wrap(err, "could not print Packer version")
 	}
-	logWriter.Flush()
+	flushLog()
 	l.Debug("printed packer version")
 
 	template, err := j.convertTemplateToJSON()
@@ -101,44 +219,163 @@ func (j *Job) Execute(ctx context.Context) error {
 	}
 	l.Debug("converted template from YAML to JSON")
 
-	recordsDir := filepath.Join(dir, "records")
-	if err = os.Mkdir(recordsDir, 0777); err != nil {
-		return err
-	}
-	l.WithField("records_path", recordsDir).Debug("created custom records directory")
-
 	l.Info("starting packer build")
 	packerSucceeded := true
-	cmd = exec.CommandContext(ctx, j.packer(), "build", "-color=false", "-var", "records_path="+recordsDir, template)
-	cmd.Stdout = logWriter
-	cmd.Stderr = logWriter
+
+	// Packer's machine-readable output goes through a parser that picks out the
+	// artifacts, and its stderr is written to the log directly, so the two need
+	// to take turns.
+	out := &lockedWriter{w: logWriter}
+	parser := newMachineReadableWriter(out)
+
+	cmd = exec.Command(j.packer(), j.buildArgs(recordsDir, template, secretVarFile)...)
+	cmd.Stdout = parser
+	cmd.Stderr = out
 	cmd.Dir = j.templatesDir()
-	if err = cmd.Run(); err != nil {
+	cmd.Env = append(os.Environ(), secretEnv...)
+	err = runCommand(ctx, cmd)
+	parser.Flush()
+
+	// Use a fresh context, since the artifacts exist even if the build was cancelled
+	j.createArtifacts(context.Background(), l, parser.Artifacts())
+
+	if err != nil {
 		if _, ok := err.(*exec.ExitError); ok {
 			l.WithError(err).Error("packer build failed")
 			packerSucceeded = false
+
+			reason := "packer build failed: " + err.Error()
+			j.Build.FailureReason = &reason
 		} else {
 			return errors.Wrap(err, "could not run Packer build")
 		}
 	} else {
 		l.Info("packer build succeeded")
 	}
-	logWriter.Flush()
-	logFile.Sync()
-
-	if err = j.createRecords(ctx, l, recordsDir); err != nil {
-		return err
-	}
 
 	if packerSucceeded {
 		j.Build.Status = db.BuildStatusSucceeded
 	}
-	l.WithField("status", j.Build.Status).Info("build finished")
 
 	return nil
 }
 
-func (j *Job) storage() *storage.Storage {
+// finish saves the outcome of the job's attempt, and then either finishes the
+// build or queues it to be attempted again.
+func (j *Job) finish(ctx context.Context, l *logrus.Entry) {
+	if j.attempt != nil {
+		j.attempt.Status = j.Build.Status
+		j.attempt.FailureReason = j.Build.FailureReason
+		if err := j.db().FinishAttempt(ctx, j.attempt); err != nil {
+			l.WithError(err).Error("could not save build attempt")
+		}
+
+		status := string(j.attempt.Status)
+		buildAttempts.WithLabelValues(j.Build.Name, status).Inc()
+		buildDuration.WithLabelValues(j.Build.Name, status).Observe(time.Since(j.started).Seconds())
+
+		if j.shouldRetry() {
+			err := j.db().QueueBuild(ctx, j.Build)
+			if err == nil {
+				l.WithField("status", j.attempt.Status).Info("queued build to be retried")
+				return
+			}
+
+			l.WithError(err).Error("could not queue build to be retried")
+		}
+	}
+
+	l.WithField("status", j.Build.Status).Info("build finished")
+	if err := j.db().FinishBuild(ctx, j.Build); err != nil {
+		l.WithError(err).Error("could not save finished build")
+		return
+	}
+
+	buildsFinished.WithLabelValues(j.Build.Name, string(j.Build.Status)).Inc()
+	j.webhooks().Notify(ctx, webhook.EventBuildFinished, j.Build)
+}
+
+// shouldRetry decides if the build should be attempted again.
+//
+// Failed and timed out builds are retried until they run out of attempts, but
+// cancelled builds never are.
+func (j *Job) shouldRetry() bool {
+	switch j.Build.Status {
+	case db.BuildStatusFailed, db.BuildStatusTimedOut:
+		return j.attempt.Number < j.worker.config.MaxAttempts
+	default:
+		return false
+	}
+}
+
+// buildArgs creates the arguments for running the Packer build, including any
+// variables that were requested for the build and the var-file of secret
+// variables, if there is one.
+func (j *Job) buildArgs(recordsDir string, template string, secretVarFile string) []string {
+	args := []string{"build", "-color=false", "-machine-readable"}
+	if secretVarFile != "" {
+		args = append(args, "-var-file="+secretVarFile)
+	}
+	args = append(args, variableArgs(j.Build, j.templatesDir(), recordsDir)...)
+	return append(args, template)
+}
+
+// variableArgs creates the Packer arguments that set the variables and
+// var-files requested for a build.
+func variableArgs(b *db.Build, templatesDir string, recordsDir string) []string {
+	var args []string
+	for _, f := range b.VarFiles {
+		args = append(args, "-var-file="+filepath.Join(templatesDir, f))
+	}
+
+	names := make([]string, 0, len(b.Variables))
+	for name := range b.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		args = append(args, "-var", name+"="+b.Variables[name])
+	}
+
+	return append(args, "-var", RecordsPathVariable+"="+recordsDir)
+}
+
+// buildTimeout returns the timeout requested for the build, or else the
+// worker's default. A timeout of zero means the build can run forever.
+//
+// Unlike the template's timeout, it's known before the templates repo is
+// checked out.
+func (j *Job) buildTimeout() time.Duration {
+	if j.Build.TimeoutSeconds != nil && *j.Build.TimeoutSeconds > 0 {
+		return time.Duration(*j.Build.TimeoutSeconds) * time.Second
+	}
+
+	return j.worker.config.BuildTimeout
+}
+
+// templateTimeout returns the template's timeout from the metadata file in the
+// templates repo, which is used instead of the worker's default. It's zero if
+// the build requested a timeout of its own, since that comes first, or if the
+// metadata doesn't set one.
+func (j *Job) templateTimeout() (time.Duration, error) {
+	if j.Build.TimeoutSeconds != nil && *j.Build.TimeoutSeconds > 0 {
+		return 0, nil
+	}
+
+	m, err := templates.ReadMetadata(j.templatesDir())
+	if err != nil {
+		return 0, err
+	}
+
+	return m.Timeout(j.Build.Name)
+}
+
+func (j *Job) secrets() *secrets.Config {
+	return j.worker.config.Secrets
+}
+
+func (j *Job) storage() storage.Storage {
 	return j.worker.config.Storage
 }
 
@@ -146,7 +383,11 @@ func (j *Job) db() *db.Connection {
 	return j.worker.config.DB
 }
 
-func (j *Job) repo() *git.Repository {
+func (j *Job) webhooks() *webhook.Dispatcher {
+	return j.worker.config.Webhooks
+}
+
+func (j *Job) repo() *templates.Repository {
 	return j.worker.repo
 }
 
@@ -162,50 +403,57 @@ func (j *Job) outputFile(name string) string {
 	return filepath.Join(j.outputDir, name)
 }
 
+// outputDirPrefix is the prefix of the temporary directory a build writes its
+// output to.
+//
+// The build ID is included so that the output can be found again if imaged
+// stops in the middle of the build.
+func outputDirPrefix(b *db.Build) string {
+	return "imaged-build-" + strconv.FormatInt(b.ID, 10) + "-"
+}
+
 func (j *Job) resetRepository(ctx context.Context) (string, error) {
 	// Fetch any new commits since the process started
-	if err := j.worker.updateTemplates(); err != nil {
+	if err := j.repo().Fetch(ctx); err != nil {
 		return "", err
 	}
 
-	// We need to resolve the reference they gave us
-	rev := "origin/" + j.Build.Revision
-	h, err := j.repo().ResolveRevision(plumbing.Revision(rev))
-	if err != nil {
-		if err == plumbing.ErrReferenceNotFound {
-			h, err = j.repo().ResolveRevision(plumbing.Revision(j.Build.Revision))
-		}
-
-		if err != nil {
-			return "", errors.Wrap(err, "could not resolve reference in templates repo")
-		}
+	// We need to resolve the reference they gave us. Once a build has a full
+	// revision, every attempt uses that commit, even if the revision has moved
+	// on. That covers automatic retries, requeued orphans and builds retried
+	// from another build.
+	rev := j.Build.Revision
+	if j.Build.FullRevision != nil {
+		rev = *j.Build.FullRevision
 	}
 
-	// Check out the resolved revision, discarding any local changes
-	w, err := j.repo().Worktree()
+	h, err := j.repo().Resolve(rev)
 	if err != nil {
-		return "", errors.Wrap(err, "could not get worktree for templates repo")
+		return "", err
 	}
-	err = w.Checkout(&git.CheckoutOptions{
-		Hash:  *h,
-		Force: true,
-	})
-	if err != nil {
-		return "", errors.Wrap(err, "could not checkout templates revision")
+
+	// Check out the resolved revision, discarding any local changes
+	if err = j.repo().Checkout(ctx, h); err != nil {
+		return "", err
 	}
 
-	rev = h.String()
-	return rev, nil
+	return h.String(), nil
 }
 
 func (j *Job) convertTemplateToJSON() (string, error) {
-	ymlPath := filepath.Join(j.templatesDir(), "templates", j.Build.Name+".yml")
+	return convertTemplateToJSON(j.templatesDir(), j.Build.Name, j.outputDir)
+}
+
+// convertTemplateToJSON converts a template in a checkout of the templates repo
+// to the JSON that Packer expects, writing it to the output directory.
+func convertTemplateToJSON(templatesDir string, name string, outputDir string) (string, error) {
+	ymlPath := filepath.Join(templatesDir, filepath.FromSlash(templates.Path(name)))
 	yml, err := ioutil.ReadFile(ymlPath)
 	if err != nil {
 		return "", errors.Wrap(err, "could not read template YAML")
 	}
 
-	jsonPath := j.outputFile(j.Build.Name + ".json")
+	jsonPath := filepath.Join(outputDir, name+".json")
 
 	json, err := yaml.YAMLToJSON(yml)
 	if err != nil {
@@ -219,29 +467,14 @@ func (j *Job) convertTemplateToJSON() (string, error) {
 	return jsonPath, nil
 }
 
-func (j *Job) installSecrets(ctx context.Context) error {
-	srcPath := j.worker.config.AnsibleSecretsFile
-	destPath := filepath.Join(j.templatesDir(), "linux_playbooks", "secrets.yml")
-
-	src, err := os.Open(srcPath)
-	if err != nil {
-		return errors.Wrap(err, "could not open secrets file")
+// cleanupSecrets removes the secret files that were installed for the build.
+func (j *Job) cleanupSecrets(l *logrus.Entry, inst *secrets.Installation) {
+	if err := inst.Cleanup(); err != nil {
+		l.WithError(err).Error("could not clean up secrets")
+		return
 	}
-	defer src.Close()
 
-	dest, err := os.Create(destPath)
-	if err != nil {
-		return errors.Wrap(err, "could not create new secrets file")
-	}
-	defer dest.Close()
-
-	if _, err = io.Copy(dest, src); err != nil {
-		return errors.Wrap(err, "could not copy secrets file contents")
-	}
-
-	dest.Sync()
-
-	return nil
+	l.Debug("cleaned up secrets")
 }
 
 func (j *Job) createRecords(ctx context.Context, l *logrus.Entry, recordsDir string) error {
@@ -281,14 +514,47 @@ func (j *Job) createRecords(ctx context.Context, l *logrus.Entry, recordsDir str
 	return nil
 }
 
+// createArtifacts saves the artifacts Packer reported during the build.
+//
+// Artifacts are saved even if the build fails, since Packer may have created
+// some before the failure.
+func (j *Job) createArtifacts(ctx context.Context, l *logrus.Entry, artifacts []*db.Artifact) {
+	for _, a := range artifacts {
+		alog := l.WithFields(logrus.Fields{
+			"builder":     a.Builder,
+			"artifact_id": a.ArtifactID,
+		})
+
+		if artifact, err := j.db().CreateArtifact(ctx, j.Build, a); err != nil {
+			alog.WithError(err).Error("failed to save artifact")
+		} else {
+			alog.WithField("id", artifact.ID).Info("saved artifact")
+		}
+	}
+}
+
 func (j *Job) createRecord(ctx context.Context, f *os.File) (*db.Record, error) {
-	name := filepath.Base(f.Name())
+	return j.uploadRecord(ctx, filepath.Base(f.Name()), f)
+}
+
+func (j *Job) uploadRecord(ctx context.Context, name string, r io.Reader) (*db.Record, error) {
 	key := j.Build.RecordKey(name)
-	if _, err := j.storage().Upload(ctx, key, f); err != nil {
-		return nil, errors.Wrap(err, "could not upload file to S3")
+	if j.attempt != nil {
+		key = j.attempt.RecordKey(name)
 	}
 
-	record, err := j.db().CreateRecord(ctx, j.Build, name, key)
+	meta, err := storage.UploadWithMetadata(ctx, j.storage(), key, name, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not upload file to storage")
+	}
+
+	record, err := j.db().CreateRecord(ctx, j.Build, j.attempt, &db.Record{
+		FileName:    name,
+		S3Key:       key,
+		SHA256:      &meta.SHA256,
+		Size:        &meta.Size,
+		ContentType: &meta.ContentType,
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create record for uploaded file")
 	}
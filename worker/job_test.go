@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"github.com/travis-ci/imaged/db"
+	"github.com/travis-ci/imaged/templates"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJobTimeouts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "imaged-templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	metadata := "templates:\n  macos:\n    timeout: 4h\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, templates.MetadataFile), []byte(metadata), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := &Worker{config: Config{TemplatesPath: dir, BuildTimeout: 2 * time.Hour}}
+	requested := int64(600)
+
+	// The build's own timeout applies from the start, and the template can't
+	// override it
+	j := &Job{worker: w, Build: &db.Build{Name: "macos", TimeoutSeconds: &requested}}
+	if d := j.buildTimeout(); d != 10*time.Minute {
+		t.Errorf("buildTimeout() with a requested timeout = %v, want 10m", d)
+	}
+	if d, err := j.templateTimeout(); d != 0 || err != nil {
+		t.Errorf("templateTimeout() with a requested timeout = %v, %v, want 0", d, err)
+	}
+
+	// Otherwise the default applies until the template's timeout is known
+	j = &Job{worker: w, Build: &db.Build{Name: "macos"}}
+	if d := j.buildTimeout(); d != 2*time.Hour {
+		t.Errorf("buildTimeout() = %v, want the default of 2h", d)
+	}
+	if d, err := j.templateTimeout(); d != 4*time.Hour || err != nil {
+		t.Errorf("templateTimeout() = %v, %v, want 4h", d, err)
+	}
+
+	j = &Job{worker: w, Build: &db.Build{Name: "linux"}}
+	if d, err := j.templateTimeout(); d != 0 || err != nil {
+		t.Errorf("templateTimeout() for a template without metadata = %v, %v, want 0", d, err)
+	}
+}
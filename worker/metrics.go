@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/travis-ci/imaged/db"
+	"time"
+)
+
+// queueDepthTimeout is how long a scrape waits for the database to count the
+// queued builds.
+const queueDepthTimeout = 5 * time.Second
+
+var (
+	buildsStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "imaged_builds_started_total",
+		Help: "Builds started by this instance, by template. Retries of a build aren't counted again.",
+	}, []string{"template"})
+	buildsFinished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "imaged_builds_finished_total",
+		Help: "Builds finished by this instance, by template and final status.",
+	}, []string{"template", "status"})
+	buildAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "imaged_build_attempts_total",
+		Help: "Build attempts finished by this instance, including ones that were retried, by template and status.",
+	}, []string{"template", "status"})
+	buildDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "imaged_build_duration_seconds",
+		Help:    "How long build attempts took, by template and status.",
+		Buckets: []float64{60, 300, 600, 1200, 1800, 3600, 7200, 14400, 21600, 43200},
+	}, []string{"template", "status"})
+	workerBusy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "imaged_worker_busy",
+		Help: "Whether each worker is running a build (1) or idle (0).",
+	}, []string{"worker"})
+
+	queueDepthDesc = prometheus.NewDesc(
+		"imaged_build_queue_depth",
+		"Builds waiting in the queue for a worker.",
+		nil, nil,
+	)
+)
+
+// queueDepthCollector reports how many builds are waiting in the queue.
+//
+// The queue is shared by every imaged instance, so it's counted in the
+// database when it's scraped rather than tracked by this instance.
+type queueDepthCollector struct {
+	db *db.Connection
+}
+
+func (c *queueDepthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+}
+
+func (c *queueDepthCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueDepthTimeout)
+	defer cancel()
+
+	n, err := c.db.CountQueuedBuilds(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(queueDepthDesc, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(n))
+}
@@ -0,0 +1,161 @@
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/travis-ci/imaged/db"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// machineReadableUnescaper undoes the escaping Packer does for the data fields
+// of its machine-readable output: commas are replaced with %!(PACKER_COMMA),
+// and carriage returns and newlines with \r and \n. Packer doesn't escape
+// backslashes, so a \n that was in the original text can't be told apart from
+// an escaped newline, and is unescaped too.
+var machineReadableUnescaper = strings.NewReplacer(
+	"%!(PACKER_COMMA)", ",",
+	`\n`, "\n",
+	`\r`, "\r",
+)
+
+// machineReadableWriter parses the output of Packer when it's run with
+// -machine-readable.
+//
+// UI messages are written to the build log the way Packer would normally print
+// them, and the artifacts Packer reports are collected so they can be saved
+// with the build. Any other lines are written to the build log as they are.
+type machineReadableWriter struct {
+	out       io.Writer
+	buf       []byte
+	artifacts []*db.Artifact
+	current   map[string]*db.Artifact
+}
+
+func newMachineReadableWriter(out io.Writer) *machineReadableWriter {
+	return &machineReadableWriter{
+		out:     out,
+		current: make(map[string]*db.Artifact),
+	}
+}
+
+// Write parses any complete lines of output.
+func (w *machineReadableWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i == -1 {
+			break
+		}
+
+		w.handleLine(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush parses any output left over after Packer has exited.
+func (w *machineReadableWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.handleLine(string(w.buf))
+		w.buf = nil
+	}
+}
+
+// Artifacts returns the artifacts Packer reported, in the order it reported them.
+func (w *machineReadableWriter) Artifacts() []*db.Artifact {
+	return w.artifacts
+}
+
+// handleLine parses a line in the format "timestamp,target,type,data...".
+func (w *machineReadableWriter) handleLine(line string) {
+	fields := strings.Split(strings.TrimSuffix(line, "\r"), ",")
+	if len(fields) < 3 {
+		// Not machine-readable, so keep it as is
+		fmt.Fprintln(w.out, line)
+		return
+	}
+
+	// Only the data fields are escaped by Packer
+	target, kind, data := fields[1], fields[2], fields[3:]
+	switch kind {
+	case "ui":
+		// The first field is the kind of message: say, message or error
+		if len(data) > 1 {
+			fmt.Fprintln(w.out, machineReadableUnescaper.Replace(data[1]))
+		}
+	case "artifact":
+		for i, f := range data {
+			data[i] = machineReadableUnescaper.Replace(f)
+		}
+		w.handleArtifact(target, data)
+	case "artifact-count":
+		// The artifacts are counted as they're reported
+	default:
+		// Keep messages that aren't handled here, like the Packer version or
+		// output that only happens to contain commas
+		fmt.Fprintln(w.out, line)
+	}
+}
+
+// handleArtifact parses the data of an artifact line, which is in the format
+// "index,key,values...".
+func (w *machineReadableWriter) handleArtifact(target string, data []string) {
+	if len(data) < 2 {
+		return
+	}
+
+	key := target + "," + data[0]
+	a := w.current[key]
+	if a == nil {
+		a = &db.Artifact{Builder: target}
+		w.current[key] = a
+		w.artifacts = append(w.artifacts, a)
+	}
+
+	switch data[1] {
+	case "builder-id":
+		if len(data) > 2 {
+			a.BuilderID = data[2]
+		}
+	case "id":
+		if len(data) > 2 {
+			a.ArtifactID = data[2]
+		}
+	case "string":
+		if len(data) > 2 {
+			a.Description = data[2]
+		}
+	case "file":
+		if len(data) > 3 {
+			if _, err := strconv.Atoi(data[2]); err == nil {
+				a.Files = append(a.Files, data[3])
+			}
+		}
+	case "nil":
+		// The builder didn't produce an artifact after all
+		for i, other := range w.artifacts {
+			if other == a {
+				w.artifacts = append(w.artifacts[:i], w.artifacts[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// lockedWriter lets more than one goroutine write to the same writer, like
+// when a command's stdout and stderr are handled separately.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.w.Write(p)
+}
@@ -0,0 +1,111 @@
+package worker
+
+import (
+	"bytes"
+	"github.com/lib/pq"
+	"github.com/travis-ci/imaged/db"
+	"reflect"
+	"testing"
+)
+
+func TestMachineReadableWriter(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    []string
+		log       string
+		artifacts []*db.Artifact
+	}{
+		{
+			name: "ui messages",
+			output: []string{
+				"1546300800,,ui,say,==> amazon-ebs: Prevalidating AMI Name...\n",
+				"1546300801,amazon-ebs,ui,message,    amazon-ebs: Found Image ID%!(PACKER_COMMA) ami-123\n",
+				"1546300802,amazon-ebs,ui,error,first line\\nsecond line\n",
+			},
+			log: "==> amazon-ebs: Prevalidating AMI Name...\n" +
+				"    amazon-ebs: Found Image ID, ami-123\n" +
+				"first line\nsecond line\n",
+		},
+		{
+			name: "other output",
+			output: []string{
+				"not machine-readable\n",
+				"1546300800,,version,1.3.3\n",
+				"1546300800,,ui\n",
+				"1546300800,,ui,say,windows line\r\n",
+				"1546300800,amazon-ebs,error,Bad AMI%!(PACKER_COMMA) see C:\\new\n",
+				"error: a, b, c\n",
+			},
+			log: "not machine-readable\n" +
+				"1546300800,,version,1.3.3\n" +
+				"windows line\n" +
+				"1546300800,amazon-ebs,error,Bad AMI%!(PACKER_COMMA) see C:\\new\n" +
+				"error: a, b, c\n",
+		},
+		{
+			name: "lines split across writes",
+			output: []string{
+				"1546300800,,ui,say,hel",
+				"lo\n1546300800,,ui,say,wor",
+				"ld",
+			},
+			log: "hello\nworld\n",
+		},
+		{
+			name: "artifacts",
+			output: []string{
+				"1546300900,amazon-ebs,artifact-count,1\n",
+				"1546300900,amazon-ebs,artifact,0,builder-id,mitchellh.amazonebs\n",
+				"1546300900,amazon-ebs,artifact,0,id,us-east-1:ami-123\n",
+				"1546300900,amazon-ebs,artifact,0,string,AMIs were created:\\nus-east-1: ami-123\n",
+				"1546300900,amazon-ebs,artifact,0,files-count,0\n",
+				"1546300900,amazon-ebs,artifact,0,end\n",
+				"1546300900,docker,artifact,0,builder-id,packer.post-processor.docker-import\n",
+				"1546300900,docker,artifact,0,file,0,image.tar\n",
+				"1546300900,docker,artifact,0,file,1,image%!(PACKER_COMMA)v2.tar\n",
+				"1546300900,docker,artifact,0,file,x,ignored.tar\n",
+			},
+			artifacts: []*db.Artifact{
+				{
+					Builder:     "amazon-ebs",
+					BuilderID:   "mitchellh.amazonebs",
+					ArtifactID:  "us-east-1:ami-123",
+					Description: "AMIs were created:\nus-east-1: ami-123",
+				},
+				{
+					Builder:   "docker",
+					BuilderID: "packer.post-processor.docker-import",
+					Files:     pq.StringArray{"image.tar", "image,v2.tar"},
+				},
+			},
+		},
+		{
+			name: "nil artifacts",
+			output: []string{
+				"1546300900,null,artifact,0,builder-id,null\n",
+				"1546300900,null,artifact,0,nil\n",
+				"1546300900,null,artifact,1,id,kept\n",
+			},
+			artifacts: []*db.Artifact{
+				{Builder: "null", ArtifactID: "kept"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		w := newMachineReadableWriter(&out)
+		for _, o := range tt.output {
+			w.Write([]byte(o))
+		}
+		w.Flush()
+
+		if out.String() != tt.log {
+			t.Errorf("%s: log = %q, want %q", tt.name, out.String(), tt.log)
+		}
+
+		if !reflect.DeepEqual(w.Artifacts(), tt.artifacts) {
+			t.Errorf("%s: artifacts = %+v, want %+v", tt.name, w.Artifacts(), tt.artifacts)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/travis-ci/imaged/db"
+	"github.com/travis-ci/imaged/templates"
+	"strconv"
+	"sync"
+)
+
+// Pool runs several workers side by side so that builds can run in parallel.
+//
+// Each worker has its own clone of the templates repository, so that the
+// checkouts and secrets of one build can't interfere with another.
+type Pool struct {
+	workers []*Worker
+}
+
+// NewPool creates a pool of workers ready to run jobs.
+//
+// The first worker uses the templates path from the config as is, and the
+// others use numbered paths next to it, like /templates-1 and /templates-2.
+func NewPool(c Config, size int) (*Pool, error) {
+	if size < 1 {
+		return nil, errors.New("a worker pool needs at least one worker")
+	}
+
+	p := &Pool{}
+	for i := 0; i < size; i++ {
+		wc := c
+		if i > 0 {
+			wc.TemplatesPath = c.TemplatesPath + "-" + strconv.Itoa(i)
+		}
+
+		w, err := New(wc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not create worker %d", i)
+		}
+		w.id = i
+		workerBusy.WithLabelValues(strconv.Itoa(i)).Set(0)
+
+		p.workers = append(p.workers, w)
+	}
+
+	if err := prometheus.Register(&queueDepthCollector{db: c.DB}); err != nil {
+		return nil, errors.Wrap(err, "could not register queue depth metric")
+	}
+
+	return p, nil
+}
+
+// Recover handles builds that were orphaned the last time imaged stopped.
+//
+// See Worker.Recover for details.
+func (p *Pool) Recover(ctx context.Context) error {
+	return p.workers[0].Recover(ctx)
+}
+
+// Validate checks that a build would be able to run before it's queued.
+//
+// See Worker.Validate for details.
+func (p *Pool) Validate(ctx context.Context, b *db.Build) error {
+	return p.workers[0].Validate(ctx, b)
+}
+
+// Templates returns the first worker's clone of the templates repo, for
+// reading templates outside of a build.
+func (p *Pool) Templates() *templates.Repository {
+	return p.workers[0].Templates()
+}
+
+// InstanceID returns the ID the workers use to claim builds.
+func (p *Pool) InstanceID() string {
+	return p.workers[0].config.InstanceID
+}
+
+// Run runs all of the workers in the pool until they stop.
+//
+// It should be called in a goroutine.
+func (p *Pool) Run() {
+	var wg sync.WaitGroup
+	for _, w := range p.workers {
+		wg.Add(1)
+		go func(w *Worker) {
+			defer wg.Done()
+			w.Run()
+		}(w)
+	}
+
+	wg.Wait()
+}
+
+// Notify tells the workers that a new build has been queued.
+//
+// Any idle worker may claim the build.
+func (p *Pool) Notify() {
+	for _, w := range p.workers {
+		w.Notify()
+	}
+}
+
+// Cancel stops a build if it's running on any worker in the pool.
+//
+// Returns false if none of the workers are running the build.
+func (p *Pool) Cancel(id int64) bool {
+	for _, w := range p.workers {
+		if w.Cancel(id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReadLog reads the build log of a build running on any worker in the pool.
+//
+// See Worker.ReadLog for details.
+func (p *Pool) ReadLog(id int64, offset int64, max int) ([]byte, bool) {
+	for _, w := range p.workers {
+		if b, ok := w.ReadLog(id, offset, max); ok {
+			return b, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/travis-ci/imaged/db"
+	"os"
+	"path/filepath"
+)
+
+// OrphanPolicy describes what should happen to builds that were left
+// unfinished because imaged stopped while they were running.
+type OrphanPolicy string
+
+// These are the supported orphan policies.
+const (
+	// OrphanPolicyFail marks orphaned builds as failed.
+	OrphanPolicyFail OrphanPolicy = "fail"
+	// OrphanPolicyRequeue puts orphaned builds back on the queue so they run again.
+	OrphanPolicyRequeue OrphanPolicy = "requeue"
+)
+
+// Recover finds orphaned builds and either fails them or requeues them,
+// depending on the orphan policy.
+//
+// Builds are orphaned if this instance was running them when it last stopped,
+// or if the instance running them stopped sending heartbeats for longer than
+// the orphan timeout. Builds that other instances are still running are left
+// alone.
+//
+// The attempt that was running is marked as failed, and any partial build log
+// left behind in the build's output directory is uploaded as one of its
+// records. This should be called before the worker starts running builds.
+func (w *Worker) Recover(ctx context.Context) error {
+	builds, err := w.config.DB.OrphanedBuilds(ctx, w.config.InstanceID, w.config.OrphanTimeout)
+	if err != nil {
+		return errors.Wrap(err, "could not find orphaned builds")
+	}
+
+	for i := range builds {
+		b := &builds[i]
+		l := log.WithFields(log.Fields{
+			"build_id": b.ID,
+			"status":   b.Status,
+			"policy":   w.config.OrphanPolicy,
+		})
+
+		reason := "imaged stopped while the build was " + string(b.Status)
+		if b.ClaimedBy != nil && *b.ClaimedBy != w.config.InstanceID {
+			reason = "imaged instance " + *b.ClaimedBy + " stopped sending heartbeats while the build was " + string(b.Status)
+		}
+
+		attempt, err := w.config.DB.LastAttempt(ctx, b)
+		if err != nil {
+			return errors.Wrap(err, "could not find attempt of orphaned build")
+		}
+		if attempt != nil && attempt.FinishedAt != nil {
+			attempt = nil
+		}
+
+		w.recoverBuildLog(ctx, l, b, attempt)
+
+		if attempt != nil {
+			attempt.Status = db.BuildStatusFailed
+			attempt.FailureReason = &reason
+			if err = w.config.DB.FinishAttempt(ctx, attempt); err != nil {
+				return errors.Wrap(err, "could not fail attempt of orphaned build")
+			}
+		}
+
+		switch w.config.OrphanPolicy {
+		case OrphanPolicyRequeue:
+			if err = w.config.DB.QueueBuild(ctx, b); err != nil {
+				return errors.Wrap(err, "could not requeue orphaned build")
+			}
+			l.Info("requeued orphaned build")
+		default:
+			b.Status = db.BuildStatusFailed
+			b.FailureReason = &reason
+			if err = w.config.DB.FinishBuild(ctx, b); err != nil {
+				return errors.Wrap(err, "could not fail orphaned build")
+			}
+			l.Info("failed orphaned build")
+		}
+	}
+
+	return nil
+}
+
+// recoverBuildLog uploads the partial build log of an orphaned build, if its
+// output directory is still around, and then removes the directory.
+//
+// Failures are only logged, since a missing log shouldn't keep the build from
+// being recovered.
+func (w *Worker) recoverBuildLog(ctx context.Context, l *log.Entry, b *db.Build, attempt *db.Attempt) {
+	dirs, err := filepath.Glob(filepath.Join(os.TempDir(), outputDirPrefix(b)+"*"))
+	if err != nil {
+		l.WithError(err).Error("could not look for orphaned build output")
+		return
+	}
+
+	for _, dir := range dirs {
+		dl := l.WithField("out_dir", dir)
+
+		f, err := os.Open(filepath.Join(dir, db.BuildLogFileName))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				dl.WithError(err).Error("could not open partial build log")
+			}
+		} else {
+			j := &Job{Build: b, worker: w, attempt: attempt}
+			if r, err := j.uploadRecord(ctx, db.BuildLogFileName, f); err != nil {
+				dl.WithError(err).Error("failed to upload partial build log")
+			} else {
+				dl.WithField("record_id", r.ID).Info("uploaded partial build log")
+			}
+			f.Close()
+		}
+
+		if err = os.RemoveAll(dir); err != nil {
+			dl.WithError(err).Error("could not remove orphaned build output")
+		}
+	}
+}
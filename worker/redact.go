@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redacted replaces secrets in build logs.
+const redacted = "[REDACTED]"
+
+// minSecretLength is the shortest value that is treated as a secret. Shorter
+// values, like "yes" or port numbers, would redact too much of the log.
+const minSecretLength = 4
+
+// redactingWriter replaces secret values with [REDACTED] before writing to
+// another writer.
+//
+// Output is held until a whole line has been written, so that a secret split
+// across writes is still found. Flush writes out a partial last line.
+type redactingWriter struct {
+	w        io.Writer
+	replacer *strings.Replacer
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// newRedactingWriter creates a writer that redacts the given secrets.
+//
+// Secrets that span several lines are redacted line by line.
+func newRedactingWriter(w io.Writer, secrets []string) *redactingWriter {
+	seen := make(map[string]bool)
+	var values []string
+	for _, s := range secrets {
+		for _, line := range strings.Split(s, "\n") {
+			line = strings.TrimSpace(line)
+			if len(line) >= minSecretLength && !seen[line] {
+				seen[line] = true
+				values = append(values, line)
+			}
+		}
+	}
+
+	// Replace longer secrets first, in case one secret contains another
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+
+	var pairs []string
+	for _, v := range values {
+		pairs = append(pairs, v, redacted)
+	}
+
+	return &redactingWriter{
+		w:        w,
+		replacer: strings.NewReplacer(pairs...),
+	}
+}
+
+// Write redacts and writes any complete lines in p, holding on to the rest.
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	i := bytes.LastIndexByte(w.buf, '\n')
+	if i == -1 {
+		return len(p), nil
+	}
+
+	lines := w.buf[:i+1]
+	if _, err := io.WriteString(w.w, w.replacer.Replace(string(lines))); err != nil {
+		return 0, err
+	}
+
+	w.buf = append(w.buf[:0], w.buf[i+1:]...)
+	return len(p), nil
+}
+
+// Flush redacts and writes a partial line that is being held.
+func (w *redactingWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	_, err := io.WriteString(w.w, w.replacer.Replace(string(w.buf)))
+	w.buf = w.buf[:0]
+	return err
+}
+
+// secretValues collects the values that should be redacted from a build log:
+// the template's secrets and the values of the configured environment
+// variables.
+func (w *Worker) secretValues(template string) ([]string, error) {
+	secrets, err := w.config.Secrets.Values(template)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range w.config.RedactEnv {
+		if v := os.Getenv(name); v != "" {
+			secrets = append(secrets, v)
+		}
+	}
+
+	return secrets, nil
+}
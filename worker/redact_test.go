@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRedactingWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets []string
+		writes  []string
+		// written is the output before Flush, and flushed is the output after
+		written string
+		flushed string
+	}{
+		{
+			name:    "no secrets",
+			writes:  []string{"hello\n", "world"},
+			written: "hello\n",
+			flushed: "hello\nworld",
+		},
+		{
+			name:    "secret",
+			secrets: []string{"hunter22"},
+			writes:  []string{"password is hunter22\n"},
+			written: "password is [REDACTED]\n",
+			flushed: "password is [REDACTED]\n",
+		},
+		{
+			name:    "secret split across writes",
+			secrets: []string{"hunter22"},
+			writes:  []string{"password is hun", "ter22, again hunter", "22\n"},
+			written: "password is [REDACTED], again [REDACTED]\n",
+			flushed: "password is [REDACTED], again [REDACTED]\n",
+		},
+		{
+			name:    "partial line",
+			secrets: []string{"hunter22"},
+			writes:  []string{"one hunter22\ntwo hunter22"},
+			written: "one [REDACTED]\n",
+			flushed: "one [REDACTED]\ntwo [REDACTED]",
+		},
+		{
+			name:    "short values",
+			secrets: []string{"yes", "22"},
+			writes:  []string{"yes, port 22\n"},
+			written: "yes, port 22\n",
+			flushed: "yes, port 22\n",
+		},
+		{
+			name:    "multi-line secret",
+			secrets: []string{"-----BEGIN KEY-----\n  abcdefgh\n-----END KEY-----\n"},
+			writes:  []string{"-----BEGIN KEY-----\nabcdefgh\n-----END KEY-----\n"},
+			written: "[REDACTED]\n[REDACTED]\n[REDACTED]\n",
+			flushed: "[REDACTED]\n[REDACTED]\n[REDACTED]\n",
+		},
+		{
+			name:    "secret inside another",
+			secrets: []string{"token", "token-1234"},
+			writes:  []string{"token-1234 token\n"},
+			written: "[REDACTED] [REDACTED]\n",
+			flushed: "[REDACTED] [REDACTED]\n",
+		},
+		{
+			name:    "repeated secrets",
+			secrets: []string{"hunter22", "hunter22", " hunter22 "},
+			writes:  []string{"hunter22\n"},
+			written: "[REDACTED]\n",
+			flushed: "[REDACTED]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		w := newRedactingWriter(&out, tt.secrets)
+		for _, s := range tt.writes {
+			if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+				t.Errorf("%s: Write(%q) = %d, %v, want %d, nil", tt.name, s, n, err, len(s))
+			}
+		}
+
+		if out.String() != tt.written {
+			t.Errorf("%s: output before Flush = %q, want %q", tt.name, out.String(), tt.written)
+		}
+
+		if err := w.Flush(); err != nil {
+			t.Errorf("%s: Flush returned error: %v", tt.name, err)
+		}
+
+		if out.String() != tt.flushed {
+			t.Errorf("%s: output after Flush = %q, want %q", tt.name, out.String(), tt.flushed)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/travis-ci/imaged/secrets"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// secretVarFileName is the name of the var-file that secret Packer variables
+// are written to, in a directory that is removed after the build.
+const secretVarFileName = "secret-variables.json"
+
+// writeSecretVarFile writes a template's secret Packer variables to a
+// var-file in a directory, so they don't show up in Packer's arguments.
+//
+// Returns "" if the template has no secret variables.
+func writeSecretVarFile(c *secrets.Config, template string, dir string) (string, error) {
+	vars, err := c.Variables(template)
+	if err != nil {
+		return "", err
+	}
+
+	if len(vars) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(vars)
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode secret variables")
+	}
+
+	p := filepath.Join(dir, secretVarFileName)
+	if err = ioutil.WriteFile(p, b, 0600); err != nil {
+		return "", errors.Wrap(err, "could not write secret variables")
+	}
+
+	return p, nil
+}
@@ -0,0 +1,138 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"github.com/pkg/errors"
+	"github.com/travis-ci/imaged/db"
+	"github.com/travis-ci/imaged/templates"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// InvalidBuildError is returned by Validate when a build's request would not
+// be able to run.
+type InvalidBuildError struct {
+	// Argument is the part of the build request that is invalid.
+	Argument string
+	// Reason describes what is wrong with the argument.
+	Reason string
+}
+
+func (e *InvalidBuildError) Error() string {
+	return e.Argument + " " + e.Reason
+}
+
+// Validate checks that a build's revision exists in the templates repo and
+// that its template exists at that revision and can be converted to JSON.
+//
+// The latest commits are fetched first, unless the revision is the hash of a
+// commit that has already been fetched, like the one of a push that was just
+// handled.
+//
+// If template validation is enabled, the template is also checked with
+// packer validate, using the variables and var-files requested for the build.
+// The checks run against the Git objects of the revision, so they don't
+// interfere with a build that's running on the worker.
+func (w *Worker) Validate(ctx context.Context, b *db.Build) error {
+	if b.Revision == "" {
+		return &InvalidBuildError{Argument: "revision", Reason: "cannot be empty"}
+	}
+
+	if !w.repo.HasCommit(b.Revision) {
+		if err := w.repo.Fetch(ctx); err != nil {
+			return err
+		}
+	}
+
+	_, h, err := w.repo.Get(b.Revision, b.Name)
+	if err != nil {
+		cause := errors.Cause(err)
+		if pe, ok := cause.(*templates.ParseError); ok {
+			return &InvalidBuildError{Argument: "name", Reason: "is not a valid template: " + pe.Err.Error()}
+		}
+
+		switch cause {
+		case templates.ErrRevisionNotFound:
+			return &InvalidBuildError{Argument: "revision", Reason: "does not exist in the templates repo"}
+		case templates.ErrTemplateNotFound:
+			return &InvalidBuildError{Argument: "name", Reason: "is not a template at revision " + b.Revision}
+		}
+
+		return err
+	}
+
+	if !w.config.ValidateTemplates {
+		return nil
+	}
+
+	dir, err := ioutil.TempDir("", "imaged-validate-")
+	if err != nil {
+		return errors.Wrap(err, "could not create validation directory")
+	}
+	defer os.RemoveAll(dir)
+
+	// Export the revision rather than checking it out, since the worktree may
+	// be in use by a build
+	templatesDir := filepath.Join(dir, "templates")
+	if err = w.repo.Export(h, templatesDir); err != nil {
+		return err
+	}
+
+	template, err := convertTemplateToJSON(templatesDir, b.Name, dir)
+	if err != nil {
+		return err
+	}
+
+	// Templates may refer to their secrets, so install them in the export too
+	installed, err := w.config.Secrets.Install(b.Name, templatesDir)
+	defer installed.Cleanup()
+	if err != nil {
+		return errors.Wrap(err, "could not install secrets")
+	}
+
+	secretVarFile, err := writeSecretVarFile(w.config.Secrets, b.Name, dir)
+	if err != nil {
+		return err
+	}
+
+	secretEnv, err := w.config.Secrets.Env(b.Name)
+	if err != nil {
+		return err
+	}
+
+	secretValues, err := w.secretValues(b.Name)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"validate"}
+	if secretVarFile != "" {
+		args = append(args, "-var-file="+secretVarFile)
+	}
+	args = append(args, variableArgs(b, templatesDir, dir)...)
+	args = append(args, template)
+
+	// The output is sent back to whoever started the build, so it's redacted
+	var out bytes.Buffer
+	redactor := newRedactingWriter(&out, secretValues)
+	cmd := exec.Command(w.config.Packer, args...)
+	cmd.Stdout = redactor
+	cmd.Stderr = redactor
+	cmd.Dir = templatesDir
+	cmd.Env = append(os.Environ(), secretEnv...)
+	err = runCommand(ctx, cmd)
+	redactor.Flush()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return &InvalidBuildError{Argument: "name", Reason: "failed packer validate: " + strings.TrimSpace(out.String())}
+		}
+
+		return errors.Wrap(err, "could not run packer validate")
+	}
+
+	return nil
+}
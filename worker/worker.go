@@ -5,15 +5,36 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/travis-ci/imaged/db"
+	"github.com/travis-ci/imaged/secrets"
 	"github.com/travis-ci/imaged/storage"
-	"gopkg.in/src-d/go-git.v4"
+	"github.com/travis-ci/imaged/templates"
+	"github.com/travis-ci/imaged/webhook"
+	"os"
+	"strconv"
+	"sync"
+	"time"
 )
 
-// Worker waits for and runs a single Packer build at a time.
+const (
+	defaultPollInterval  = 10 * time.Second
+	defaultOrphanTimeout = 5 * time.Minute
+
+	// heartbeatInterval is how often a worker records that it's still running
+	// its build, so that other instances don't treat the build as orphaned.
+	heartbeatInterval = 30 * time.Second
+)
+
+// Worker claims queued builds and runs a single Packer build at a time.
 type Worker struct {
-	jobs   chan Job
+	id     int
+	wakeup chan struct{}
 	config Config
-	repo   *git.Repository
+	repo   *templates.Repository
+
+	mu      sync.Mutex
+	running int64
+	cancel  context.CancelFunc
+	logPath string
 }
 
 // Config contains options for configuring a new Worker.
@@ -22,20 +43,69 @@ type Config struct {
 	TemplatesPath string
 	// TemplatesURL is the URL where the Packer templates should be cloned from.
 	TemplatesURL string
+	// TemplatesAuth is how the templates repo and its submodules are cloned
+	// and fetched.
+	TemplatesAuth *templates.Auth
 	// Packer is the path to the Packer executable.
 	Packer string
-	// AnsibleSecretsFile is the path to a YAML file including secrets for use in Ansible during the builds.
-	AnsibleSecretsFile string
+	// Secrets decides which secrets are installed for each template's builds.
+	Secrets *secrets.Config
 	// DB is the database connection jobs should use.
 	DB *db.Connection
 	// Storage is the storage jobs should use to upload records.
-	Storage *storage.Storage
+	Storage storage.Storage
+	// Webhooks sends webhooks when builds start and finish.
+	Webhooks *webhook.Dispatcher
+	// PollInterval is how often the worker checks the queue for new builds when it isn't notified of them.
+	PollInterval time.Duration
+	// OrphanPolicy is what Recover does with builds that were interrupted when imaged last stopped.
+	OrphanPolicy OrphanPolicy
+	// OrphanTimeout is how long a build can go without a heartbeat from the instance running it before Recover treats it as orphaned. It should be several times the heartbeat interval of 30 seconds.
+	OrphanTimeout time.Duration
+	// InstanceID identifies this imaged instance as the owner of the builds it claims. It should stay the same when imaged restarts. Defaults to the hostname.
+	InstanceID string
+	// BuildTimeout is how long a build can run when neither the build nor its template sets a timeout.
+	BuildTimeout time.Duration
+	// MaxAttempts is how many times a build is attempted before it's left failed. Defaults to 1.
+	MaxAttempts int
+	// ValidateTemplates makes Validate run packer validate on a build's template before it's queued.
+	ValidateTemplates bool
+	// RedactEnv are environment variables whose values are redacted from build logs, along with the values of the build's secrets.
+	RedactEnv []string
 }
 
 // New creates a new worker ready to run jobs.
 func New(c Config) (*Worker, error) {
+	if c.PollInterval == 0 {
+		c.PollInterval = defaultPollInterval
+	}
+
+	if c.MaxAttempts < 1 {
+		c.MaxAttempts = 1
+	}
+
+	if c.OrphanTimeout == 0 {
+		c.OrphanTimeout = defaultOrphanTimeout
+	}
+
+	if c.InstanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get hostname for instance ID")
+		}
+		c.InstanceID = hostname
+	}
+
+	switch c.OrphanPolicy {
+	case "":
+		c.OrphanPolicy = OrphanPolicyFail
+	case OrphanPolicyFail, OrphanPolicyRequeue:
+	default:
+		return nil, errors.Errorf("unknown orphan policy %q", c.OrphanPolicy)
+	}
+
 	w := &Worker{
-		jobs:   make(chan Job),
+		wakeup: make(chan struct{}, 1),
 		config: c,
 	}
 
@@ -46,79 +116,181 @@ func New(c Config) (*Worker, error) {
 	return w, nil
 }
 
-// Send asks the worker to run a job.
+// Notify tells the worker that a new build has been queued.
 //
-// Returns an error if a job is already running on the worker.
-func (w *Worker) Send(j Job) error {
-	j.worker = w
-
+// The worker will check the queue right away instead of waiting to poll it
+// again. Notify never blocks, even if the worker is busy running a build.
+func (w *Worker) Notify() {
 	select {
-	case w.jobs <- j:
-		return nil
+	case w.wakeup <- struct{}{}:
 	default:
-		return errors.New("a job is already running on this worker")
 	}
 }
 
-// Run waits for new jobs and runs them as they come in.
+// Run claims queued builds and runs them one at a time, in the order they
+// were queued.
 //
 // It should be called in a goroutine.
 func (w *Worker) Run() {
-	for j := range w.jobs {
-		ctx := context.Background()
+	for {
+		build, err := w.config.DB.ClaimBuild(context.Background(), w.config.InstanceID)
+		if err != nil {
+			log.WithField("worker", w.id).WithError(err).Error("could not claim a queued build")
+		}
 
-		if err := j.Execute(ctx); err != nil {
-			log.WithField("build_id", j.Build.ID).WithError(err).Error("build failed")
+		if build == nil {
+			w.wait()
+			continue
 		}
+
+		w.run(build)
 	}
 }
 
-func (w *Worker) initTemplates() error {
-	if w.config.TemplatesPath == "" {
-		return errors.New("a templates path is required when creating a worker")
+// Cancel stops a build if it's currently running on this worker.
+//
+// Returns false if the worker is not running the build.
+func (w *Worker) Cancel(id int64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel == nil || w.running != id {
+		return false
 	}
 
-	r, err := git.PlainOpen(w.config.TemplatesPath)
+	w.cancel()
+	return true
+}
+
+// ReadLog reads the build log of a build while it's running on this worker.
+//
+// Up to max bytes are read from the log file on disk, starting at the given
+// byte offset. Returns false if the worker is not running the build.
+func (w *Worker) ReadLog(id int64, offset int64, max int) ([]byte, bool) {
+	w.mu.Lock()
+	running, path := w.running, w.logPath
+	w.mu.Unlock()
+
+	if running != id {
+		return nil, false
+	}
+
+	if path == "" {
+		// The build hasn't started logging yet
+		return nil, true
+	}
+
+	b, err := readLog(path, offset, max)
 	if err != nil {
-		if err == git.ErrRepositoryNotExists {
-			r, err = w.cloneTemplates()
-			if err != nil {
-				return err
-			}
-		} else {
-			return errors.Wrap(err, "could not open existing templates repo")
+		// The log is removed once the build is done with it
+		if !os.IsNotExist(err) {
+			log.WithFields(log.Fields{
+				"worker":   w.id,
+				"build_id": id,
+			}).WithError(err).Error("could not read build log")
 		}
+		return nil, false
 	}
 
-	w.repo = r
-	if err = w.updateTemplates(); err != nil {
-		return err
-	}
+	return b, true
+}
 
-	return nil
+// setLog records where the log of the running build is written, so that it
+// can be read by ReadLog.
+func (w *Worker) setLog(id int64, path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.running == id {
+		w.logPath = path
+	}
 }
 
-func (w *Worker) cloneTemplates() (*git.Repository, error) {
-	if w.config.TemplatesURL == "" {
-		return nil, errors.New("a templates URL is required when templates are not already cloned")
+// run executes a claimed build with its own cancellable context.
+func (w *Worker) run(b *db.Build) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w.mu.Lock()
+	w.running, w.cancel = b.ID, cancel
+	w.mu.Unlock()
+	workerBusy.WithLabelValues(strconv.Itoa(w.id)).Set(1)
+
+	defer func() {
+		w.mu.Lock()
+		w.running, w.cancel, w.logPath = 0, nil, ""
+		w.mu.Unlock()
+		workerBusy.WithLabelValues(strconv.Itoa(w.id)).Set(0)
+	}()
+
+	stopHeartbeat := make(chan struct{})
+	defer close(stopHeartbeat)
+	go w.heartbeat(b.ID, cancel, stopHeartbeat)
+
+	j := Job{Build: b, worker: w}
+	if err := j.Execute(ctx); err != nil {
+		log.WithFields(log.Fields{
+			"worker":   w.id,
+			"build_id": b.ID,
+		}).WithError(err).Error("build failed")
 	}
+}
 
-	r, err := git.PlainClone(w.config.TemplatesPath, false, &git.CloneOptions{
-		URL: w.config.TemplatesURL,
-	})
-	if err != nil {
-		return nil, errors.Wrap(err, "could not clone templates repo")
+// heartbeat records that the worker is still running a build until it's
+// stopped.
+//
+// The build is cancelled if the heartbeat finds that it was cancelled through
+// another imaged instance.
+func (w *Worker) heartbeat(id int64, cancel context.CancelFunc, stop <-chan struct{}) {
+	b := &db.Build{ID: id}
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		l := log.WithFields(log.Fields{
+			"worker":   w.id,
+			"build_id": b.ID,
+		})
+
+		cancelRequested, err := w.config.DB.Heartbeat(context.Background(), b, w.config.InstanceID)
+		if err != nil {
+			l.WithError(err).Error("could not record build heartbeat")
+			continue
+		}
+
+		if cancelRequested {
+			l.Info("cancelling build at the request of another instance")
+			cancel()
+		}
 	}
+}
 
-	return r, nil
+// wait blocks until the worker is notified of a new build or it's time to
+// poll the queue again.
+func (w *Worker) wait() {
+	select {
+	case <-w.wakeup:
+	case <-time.After(w.config.PollInterval):
+	}
 }
 
-func (w *Worker) updateTemplates() error {
-	if err := w.repo.Fetch(&git.FetchOptions{RemoteName: "origin"}); err != nil {
-		if err != git.NoErrAlreadyUpToDate {
-			return errors.Wrap(err, "could not fetch latest commits for templates repo")
-		}
+func (w *Worker) initTemplates() error {
+	r, err := templates.Open(w.config.TemplatesPath, w.config.TemplatesURL, w.config.TemplatesAuth)
+	if err != nil {
+		return err
 	}
 
+	w.repo = r
 	return nil
 }
+
+// Templates returns the worker's clone of the templates repo.
+func (w *Worker) Templates() *templates.Repository {
+	return w.repo
+}